@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"maze-game/game"
+	"net/http"
+	"time"
+)
+
+// Handler for today's daily challenge parameters.
+// Endpoint: GET /api/daily
+func DailyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.Daily(time.Now()))
+}
+
+type DailyLeaderboardResponse struct {
+	Date    string                  `json:"date"`
+	Entries []game.LeaderboardEntry `json:"entries"`
+}
+
+// Handler for the daily leaderboard.
+// Endpoint: GET /api/daily/leaderboard?date=YYYY-MM-DD (defaults to today, UTC)
+func DailyLeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		date = game.Daily(time.Now()).Date
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		http.Error(w, "Invalid date, use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(DailyLeaderboardResponse{
+		Date:    date,
+		Entries: game.DailyLeaderboard(date),
+	})
+}
@@ -2,16 +2,37 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"maze-game/auth"
 	"maze-game/game"
 	"maze-game/models"
-	"maze-game/store"
+	"maze-game/render"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Request/Response Structs
 type StartGameRequest struct {
-	Rows int `json:"rows"`
-	Cols int `json:"cols"`
+	Rows      int      `json:"rows"`
+	Cols      int      `json:"cols"`
+	Seed      int64    `json:"seed,omitempty"`
+	Algorithm string   `json:"algorithm,omitempty"`
+	Braiding  *float64 `json:"braiding,omitempty"`
+	Mode      string   `json:"mode,omitempty"`
+	// Share of path cells with a question (0 to 0.5), 0.05 by default in quiz mode.
+	QuestionDensity *float64 `json:"question_density,omitempty"`
+	// Question walls on the route to the exit (0 to 10), 2 by default in quiz mode.
+	QuestionWalls int `json:"question_walls,omitempty"`
+	// Share code of another game; overrides the maze settings above.
+	Code string `json:"code,omitempty"`
+	// Play a stored custom level instead of a generated maze.
+	LevelID    string `json:"level_id,omitempty"`
+	PlayerName string `json:"player_name,omitempty"`
+	// Optional time limit in seconds (time_attack picks one by itself).
+	TimeLimit int `json:"time_limit,omitempty"`
 }
 
 // Handler for starting a new game.
@@ -22,6 +43,61 @@ func StartGameHandler(w http.ResponseWriter, r *http.Request) {
 	var req StartGameRequest
 	_ = json.NewDecoder(r.Body).Decode(&req)
 
+	if req.TimeLimit < 0 {
+		http.Error(w, "Time limit must be positive", http.StatusBadRequest)
+		return
+	}
+
+	newGame, status, err := startGame(req)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// The daily challenge is the same for everyone, including its clock.
+	if req.TimeLimit > 0 && newGame.Mode != game.ModeDaily {
+		game.SetTimeLimit(newGame, time.Duration(req.TimeLimit)*time.Second)
+	}
+
+	json.NewEncoder(w).Encode(game.Snapshot(newGame))
+}
+
+// startGame creates the game described by a start request. On failure it
+// returns the HTTP status to answer with.
+func startGame(req StartGameRequest) (*models.GameState, int, error) {
+	if req.LevelID != "" {
+		level, exists := game.GetLevel(req.LevelID)
+		if !exists {
+			return nil, http.StatusNotFound, fmt.Errorf("Level not found")
+		}
+		newGame := game.NewGameFromLevel(level)
+		newGame.Player.Name = req.PlayerName
+		return newGame, http.StatusOK, nil
+	}
+
+	if req.Mode == game.ModeDaily {
+		newGame, err := game.NewDailyGame(req.PlayerName)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return newGame, http.StatusOK, nil
+	}
+
+	if req.Code != "" {
+		opts, err := game.DecodeCode(req.Code)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		// Daily results only count through mode "daily", a shared daily
+		// code is played as a classic game.
+		if opts.Mode == game.ModeDaily {
+			opts.Mode = game.ModeClassic
+		}
+		newGame := game.NewGameWithOptions(opts)
+		newGame.Player.Name = req.PlayerName
+		return newGame, http.StatusOK, nil
+	}
+
 	// Default dimensions
 	if req.Rows <= 0 {
 		req.Rows = 10
@@ -29,11 +105,91 @@ func StartGameHandler(w http.ResponseWriter, r *http.Request) {
 	if req.Cols <= 0 {
 		req.Cols = 10
 	}
+	if req.Rows > game.MaxBoardSize || req.Cols > game.MaxBoardSize {
+		return nil, http.StatusBadRequest, fmt.Errorf("Board is too large")
+	}
+	if !game.ValidAlgorithm(req.Algorithm) {
+		return nil, http.StatusBadRequest, fmt.Errorf("Unknown algorithm")
+	}
+	if !game.ValidMode(req.Mode) {
+		return nil, http.StatusBadRequest, fmt.Errorf("Unknown mode")
+	}
+	braiding := game.DefaultBraiding
+	if req.Braiding != nil {
+		if *req.Braiding < 0 || *req.Braiding > 1 {
+			return nil, http.StatusBadRequest, fmt.Errorf("Braiding must be between 0 and 1")
+		}
+		braiding = *req.Braiding
+	}
+	var density float64
+	if req.QuestionDensity != nil {
+		if *req.QuestionDensity < 0 || *req.QuestionDensity > game.MaxQuestionDensity {
+			return nil, http.StatusBadRequest, fmt.Errorf("Question density must be between 0 and %g", game.MaxQuestionDensity)
+		}
+		density = *req.QuestionDensity
+	}
+	if req.QuestionWalls < 0 || req.QuestionWalls > game.MaxQuestionWalls {
+		return nil, http.StatusBadRequest, fmt.Errorf("Question walls must be between 0 and %d", game.MaxQuestionWalls)
+	}
 
 	// Create a new game instance.
-	newGame := game.NewGame(req.Rows, req.Cols)
+	newGame := game.NewGameWithOptions(game.GameOptions{
+		Maze: game.MazeOptions{
+			Rows:      req.Rows,
+			Cols:      req.Cols,
+			Seed:      req.Seed,
+			Algorithm: req.Algorithm,
+			Braiding:  braiding,
+		},
+		Mode:            req.Mode,
+		QuestionDensity: density,
+		QuestionWalls:   req.QuestionWalls,
+	})
+	newGame.Player.Name = req.PlayerName
 
-	json.NewEncoder(w).Encode(newGame)
+	return newGame, http.StatusOK, nil
+}
+
+// Largest ASCII maze we accept (1 MB).
+const maxASCIISize = 1 << 20
+
+// Handler for starting a game on an uploaded ASCII maze.
+// Endpoint: POST /api/game/ascii (Content-Type: text/plain)
+func StartASCIIGameHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxASCIISize))
+	if err != nil {
+		http.Error(w, "Maze is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	board, err := game.ParseASCII(string(body))
+	if err != nil {
+		http.Error(w, "Invalid maze: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if problems := game.ValidateBoard(board); len(problems) > 0 {
+		http.Error(w, "Invalid maze: "+strings.Join(problems, "; "), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(game.Snapshot(game.NewGameFromBoard(board)))
+}
+
+// Handler for downloading the maze of a game as ASCII.
+// Endpoint: GET /api/game/{id}/ascii
+func ASCIIHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	gameInstance, exists := game.GetGame(id)
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"maze-"+id+".txt\"")
+	io.WriteString(w, game.FormatASCII(game.SnapshotBoard(gameInstance)))
 }
 
 // Request/Response Structs
@@ -42,9 +198,9 @@ type MoveRequest struct {
 }
 
 type MoveResponse struct {
-	Result    string            `json:"result"`
-	GameState *models.GameState `json:"game_state"`
-	Question  *models.Question  `json:"question,omitempty"`
+	Result    string                 `json:"result"`
+	GameState *models.GameState      `json:"game_state"`
+	Question  *models.PublicQuestion `json:"question,omitempty"`
 }
 
 // Handler for moving the player.
@@ -79,7 +235,7 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 	// 5. Respond with the result.
 	response := MoveResponse{
 		Result:    result,
-		GameState: gameInstance,
+		GameState: game.Snapshot(gameInstance),
 	}
 
 	// Pick random question if needed
@@ -91,27 +247,18 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 
 		if qID >= 0 {
 			// Find in bank
-			for _, q := range store.QuestionBank {
-				if q.ID == qID {
-					response.Question = &q
-					break
-				}
+			if q, ok := game.GetQuestion(qID); ok {
+				response.Question = q.Public()
 			}
 		}
-
-		// Fallback
-		if response.Question == nil && len(store.QuestionBank) > 0 {
-			q := store.QuestionBank[0]
-			response.Question = &q
-		}
 	}
 
 	json.NewEncoder(w).Encode(response)
 }
 
 type AnswerRequest struct {
-	QuestionID int    `json:"question_id"`
-	Answer     string `json:"answer"`
+	QuestionID int           `json:"question_id"`
+	Answer     models.Answer `json:"answer"`
 }
 
 // Handler for answering a question.
@@ -141,3 +288,150 @@ func AnswerHandler(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(result)
 }
+
+// Handler for fetching the full move history of a game.
+// Endpoint: GET /api/game/{id}/replay
+func ReplayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := r.PathValue("id")
+
+	gameInstance, exists := game.GetGame(id)
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(game.BuildReplay(gameInstance))
+}
+
+// Handler for exporting a finished run as an animated GIF.
+// Endpoint: GET /api/game/{id}/gif?fps=10&cell=8
+func GIFHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	gameInstance, exists := game.GetGame(id)
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+	if !game.Finished(gameInstance) {
+		http.Error(w, "Game is not finished yet", http.StatusConflict)
+		return
+	}
+
+	// Invalid or missing values fall back to the renderer defaults.
+	fps, _ := strconv.Atoi(r.URL.Query().Get("fps"))
+	cellSize, _ := strconv.Atoi(r.URL.Query().Get("cell"))
+
+	replay := game.BuildReplay(gameInstance)
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Content-Disposition", "inline; filename=\"maze-"+id+".gif\"")
+	opts := render.GIFOptions{CellSize: cellSize, FPS: fps}
+	if err := render.GIF(w, replay.Board, replay.Path(), opts); err != nil {
+		http.Error(w, "Failed to render GIF", http.StatusInternalServerError)
+	}
+}
+
+// Handler for rendering a board as an image.
+// Endpoint: GET /api/game/{id}/image?format=svg|png&solution=true&cell=8
+func ImageHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	gameInstance, exists := game.GetGame(id)
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	cellSize, _ := strconv.Atoi(query.Get("cell"))
+	opts := render.Options{CellSize: cellSize}
+	board := game.SnapshotBoard(gameInstance)
+
+	// The solution would spoil an active game, so only admins get it early.
+	if query.Get("solution") == "true" {
+		if !game.Finished(gameInstance) && !auth.IsAdmin(r) {
+			http.Error(w, "Solution is only available after the game ends", http.StatusForbidden)
+			return
+		}
+		opts.Solution = game.SolveMaze(board)
+	}
+
+	var err error
+	switch query.Get("format") {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		err = render.SVG(w, board, opts)
+	case "png":
+		w.Header().Set("Content-Type", "image/png")
+		err = render.PNG(w, board, opts)
+	default:
+		http.Error(w, "Unsupported format, use svg or png", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to render image", http.StatusInternalServerError)
+	}
+}
+
+// Handler for pausing a game.
+// Endpoint: POST /api/game/{id}/pause
+func PauseHandler(w http.ResponseWriter, r *http.Request) {
+	pauseOrResume(w, r, game.PauseGame)
+}
+
+// Handler for resuming a paused game.
+// Endpoint: POST /api/game/{id}/resume
+func ResumeHandler(w http.ResponseWriter, r *http.Request) {
+	pauseOrResume(w, r, game.ResumeGame)
+}
+
+func pauseOrResume(w http.ResponseWriter, r *http.Request, action func(*models.GameState) (*models.GameState, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	gameInstance, exists := game.GetGame(r.PathValue("id"))
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	state, err := action(gameInstance)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	json.NewEncoder(w).Encode(state)
+}
+
+// HintResponse carries the hint and the game after paying for it.
+type HintResponse struct {
+	Hint      *game.Hint        `json:"hint"`
+	GameState *models.GameState `json:"game_state"`
+}
+
+// Handler for asking the way to the exit.
+// Endpoint: POST /api/game/{id}/hint?cells=3
+func HintHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	gameInstance, exists := game.GetGame(r.PathValue("id"))
+	if !exists {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+
+	cells, _ := strconv.Atoi(r.URL.Query().Get("cells"))
+	hint, err := game.GetHint(gameInstance, cells)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	json.NewEncoder(w).Encode(HintResponse{
+		Hint:      hint,
+		GameState: game.Snapshot(gameInstance),
+	})
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"maze-game/game"
+	"net/http"
+	"strconv"
+)
+
+type LeaderboardResponse struct {
+	Entries  []game.LeaderboardEntry `json:"entries"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	Total    int                     `json:"total"`
+}
+
+// Handler for the leaderboards.
+// Endpoint: GET /api/leaderboard?seed=42&rows=21&cols=21&code=...&mode=classic&page=1&page_size=20
+// Without filters this is the overall leaderboard.
+func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	q := game.LeaderboardQuery{
+		Code: query.Get("code"),
+		Mode: query.Get("mode"),
+	}
+
+	// Every numeric parameter is optional but must be valid when given.
+	for name, dst := range map[string]*int{"rows": &q.Rows, "cols": &q.Cols, "page": &q.Page, "page_size": &q.PageSize} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "Invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = n
+		}
+	}
+	if v := query.Get("seed"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid seed", http.StatusBadRequest)
+			return
+		}
+		q.Seed = seed
+	}
+
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = game.DefaultPageSize
+	}
+	q.PageSize = min(q.PageSize, game.MaxPageSize)
+
+	entries, total := game.Leaderboard(q)
+	json.NewEncoder(w).Encode(LeaderboardResponse{
+		Entries:  entries,
+		Page:     q.Page,
+		PageSize: q.PageSize,
+		Total:    total,
+	})
+}
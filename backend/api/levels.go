@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"maze-game/game"
+	"maze-game/models"
+	"net/http"
+	"strings"
+)
+
+// Request/Response Structs
+type LevelResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+	Cols int    `json:"cols"`
+}
+
+type LevelErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// Handler for uploading a custom level.
+// Endpoint: POST /api/levels
+// Accepts a JSON LevelDefinition, or an ASCII maze with Content-Type text/plain
+// (the level name then comes from ?name=...).
+func CreateLevelHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxASCIISize))
+	if err != nil {
+		http.Error(w, "Level is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var name string
+	var board models.Board
+	var problems []string
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/plain") {
+		name = r.URL.Query().Get("name")
+		board, err = game.ParseASCII(string(body))
+		if err != nil {
+			problems = []string{err.Error()}
+		}
+	} else {
+		var def game.LevelDefinition
+		if err := json.Unmarshal(body, &def); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		name = def.Name
+		board, problems = game.BuildLevelBoard(def)
+	}
+
+	// Report structural problems too, as long as we got a board at all.
+	if board.Rows > 0 {
+		problems = append(problems, game.ValidateBoard(board)...)
+	}
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(LevelErrorResponse{Errors: problems})
+		return
+	}
+
+	level := game.SaveLevel(name, board)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(LevelResponse{
+		ID:   level.ID,
+		Name: level.Name,
+		Rows: level.Board.Rows,
+		Cols: level.Board.Cols,
+	})
+}
+
+// Handler for fetching a stored level.
+// Endpoint: GET /api/levels/{id}
+func GetLevelHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	level, exists := game.GetLevel(r.PathValue("id"))
+	if !exists {
+		http.Error(w, "Level not found", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(level)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"maze-game/game"
+	"maze-game/render"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Limits for a single puzzle book request.
+const (
+	maxBookPuzzles = 50
+	maxBookSize    = 101
+)
+
+// Handler for generating a printable puzzle book.
+// Endpoint: GET /api/puzzlebook?count=6&rows=21&cols=21&seed=42&algorithm=prim&difficulty=hard
+// Pass seeds=1,2,3 instead of count/seed to pick the exact mazes.
+func PuzzleBookHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	rows, _ := strconv.Atoi(query.Get("rows"))
+	cols, _ := strconv.Atoi(query.Get("cols"))
+	if rows <= 0 {
+		rows = 21
+	}
+	if cols <= 0 {
+		cols = 21
+	}
+	if rows > maxBookSize || cols > maxBookSize {
+		http.Error(w, fmt.Sprintf("Mazes can be at most %dx%d", maxBookSize, maxBookSize), http.StatusBadRequest)
+		return
+	}
+
+	algorithm := query.Get("algorithm")
+	if !game.ValidAlgorithm(algorithm) {
+		http.Error(w, "Unknown algorithm", http.StatusBadRequest)
+		return
+	}
+	if algorithm == "" {
+		algorithm = game.AlgorithmBacktracking
+	}
+
+	difficulty := query.Get("difficulty")
+	braiding, ok := game.BraidingFor(difficulty)
+	if !ok {
+		http.Error(w, "Unknown difficulty, use easy, medium or hard", http.StatusBadRequest)
+		return
+	}
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+
+	seeds, err := bookSeeds(query.Get("seeds"), query.Get("seed"), query.Get("count"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	puzzles := make([]render.Puzzle, len(seeds))
+	for i, seed := range seeds {
+		board := game.GenerateMazeWithOptions(game.MazeOptions{
+			Rows:      rows,
+			Cols:      cols,
+			Seed:      seed,
+			Algorithm: algorithm,
+			Braiding:  braiding,
+		})
+		puzzles[i] = render.Puzzle{
+			Title:    fmt.Sprintf("Maze %d", i+1),
+			Subtitle: fmt.Sprintf("%dx%d - %s - %s - seed %d", rows, cols, algorithm, difficulty, board.Seed),
+			Board:    board,
+			Solution: game.SolveMaze(board),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"maze-puzzle-book.pdf\"")
+	if err := render.PuzzleBook(w, puzzles); err != nil {
+		http.Error(w, "Failed to render puzzle book", http.StatusInternalServerError)
+	}
+}
+
+// bookSeeds resolves the seeds to print: an explicit list, or count
+// consecutive seeds from a base seed (random when not given).
+func bookSeeds(list, base, count string) ([]int64, error) {
+	if list != "" {
+		var seeds []int64
+		for _, s := range strings.Split(list, ",") {
+			seed, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			if err != nil || seed == 0 {
+				return nil, fmt.Errorf("invalid seed %q", s)
+			}
+			seeds = append(seeds, seed)
+		}
+		if len(seeds) > maxBookPuzzles {
+			return nil, fmt.Errorf("at most %d mazes per book", maxBookPuzzles)
+		}
+		return seeds, nil
+	}
+
+	n := 6
+	if count != "" {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid count %q", count)
+		}
+	}
+	if n > maxBookPuzzles {
+		return nil, fmt.Errorf("at most %d mazes per book", maxBookPuzzles)
+	}
+
+	seeds := make([]int64, n)
+	if base == "" {
+		// Leave seeds at 0 so every maze gets a random one.
+		return seeds, nil
+	}
+	start, err := strconv.ParseInt(base, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed %q", base)
+	}
+	for i := range seeds {
+		seeds[i] = start + int64(i)
+		if seeds[i] == 0 {
+			// 0 means "random" to the generator, skip it.
+			start++
+			seeds[i] = start + int64(i)
+		}
+	}
+	return seeds, nil
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"maze-game/models"
+	"maze-game/store"
+	"net/http"
+	"strconv"
+)
+
+// QuestionListResponse is the admin view of the bank, answers included.
+type QuestionListResponse struct {
+	Questions []models.Question `json:"questions"`
+	Total     int               `json:"total"`
+}
+
+type QuestionErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// Handler for listing the question bank.
+// Endpoint: GET /api/admin/questions?difficulty=...&category=...
+func ListQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	bank := store.Questions()
+
+	questions := bank.All()
+	if difficulty := query.Get("difficulty"); difficulty != "" {
+		questions = bank.ByDifficulty(difficulty)
+	}
+	if category := query.Get("category"); category != "" {
+		filtered := questions[:0]
+		for _, q := range questions {
+			if q.Category == category {
+				filtered = append(filtered, q)
+			}
+		}
+		questions = filtered
+	}
+	if questions == nil {
+		questions = []models.Question{}
+	}
+
+	json.NewEncoder(w).Encode(QuestionListResponse{
+		Questions: questions,
+		Total:     len(questions),
+	})
+}
+
+// Handler for adding a question. The ID is assigned when left out.
+// Endpoint: POST /api/admin/questions
+func CreateQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var q models.Question
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := store.CreateQuestion(q)
+	if err != nil {
+		writeQuestionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// Handler for replacing a question.
+// Endpoint: PUT /api/admin/questions/{id}
+func UpdateQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Question not found", http.StatusNotFound)
+		return
+	}
+
+	var q models.Question
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := store.UpdateQuestion(id, q)
+	if err != nil {
+		writeQuestionError(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Handler for removing a question.
+// Endpoint: DELETE /api/admin/questions/{id}
+func DeleteQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Question not found", http.StatusNotFound)
+		return
+	}
+
+	if err := store.DeleteQuestion(id); err != nil {
+		writeQuestionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeQuestionError maps an error from editing the bank to a response.
+func writeQuestionError(w http.ResponseWriter, err error) {
+	var invalid *store.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		problems := make([]string, len(invalid.Problems))
+		for i, p := range invalid.Problems {
+			problems[i] = p.Message
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(QuestionErrorResponse{Errors: problems})
+	case errors.Is(err, store.ErrQuestionNotFound):
+		http.Error(w, "Question not found", http.StatusNotFound)
+	case errors.Is(err, store.ErrQuestionExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Failed to save questions: "+err.Error(), http.StatusInternalServerError)
+	}
+}
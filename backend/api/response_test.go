@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"maze-game/game"
+	"maze-game/models"
+	"maze-game/socket"
+)
+
+// Every type that is sent to players, over HTTP or WebSocket.
+var responseTypes = []any{
+	MoveResponse{},
+	HintResponse{},
+	LevelResponse{},
+	LeaderboardResponse{},
+	DailyLeaderboardResponse{},
+	game.AnswerResult{},
+	game.Replay{},
+	socket.WSUpdatePayload{},
+}
+
+// JSON fields that give an answer away.
+var answerTags = map[string]bool{"correct_ans": true, "correct_answers": true, "aliases": true}
+
+// TestResponsesNeverCarryAnswers walks every field reachable from the
+// response types and fails if one could hold a question's answer.
+func TestResponsesNeverCarryAnswers(t *testing.T) {
+	question := reflect.TypeOf(models.Question{})
+	seen := make(map[reflect.Type]bool)
+
+	var walk func(typ reflect.Type, path string)
+	walk = func(typ reflect.Type, path string) {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			walk(typ.Elem(), path)
+			return
+		case reflect.Struct:
+		default:
+			return
+		}
+		if typ == question {
+			t.Errorf("%s is a models.Question, use models.PublicQuestion", path)
+			return
+		}
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			tag := strings.Split(field.Tag.Get("json"), ",")[0]
+			if tag == "-" || !field.IsExported() {
+				continue
+			}
+			if answerTags[tag] {
+				t.Errorf("%s.%s is sent as %s", path, field.Name, tag)
+			}
+			walk(field.Type, path+"."+field.Name)
+		}
+	}
+
+	for _, r := range responseTypes {
+		typ := reflect.TypeOf(r)
+		walk(typ, typ.String())
+	}
+}
+
+func TestMoveResponseHidesAnswer(t *testing.T) {
+	q := models.Question{ID: 1, Text: "2 + 2?", Options: []string{"3", "4"}, CorrectAns: "secret-answer", Difficulty: "easy"}
+
+	body, err := json.Marshal(MoveResponse{Result: "QuestionFound", Question: q.Public()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "secret-answer") || strings.Contains(string(body), "correct_ans") {
+		t.Errorf("response leaks the answer: %s", body)
+	}
+}
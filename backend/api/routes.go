@@ -1,6 +1,7 @@
 package api
 
 import (
+	"maze-game/auth"
 	"maze-game/socket"
 	"net/http"
 )
@@ -28,11 +29,34 @@ func NewRouter() http.Handler {
 
 	// Map URL paths to the handler functions we defined in handlers.go.
 	mux.HandleFunc("POST /api/game/start", StartGameHandler)
+	mux.HandleFunc("POST /api/game/ascii", StartASCIIGameHandler)
 	mux.HandleFunc("POST /api/game/{id}/move", MoveHandler)
 	mux.HandleFunc("POST /api/game/{id}/answer", AnswerHandler)
+	mux.HandleFunc("POST /api/game/{id}/pause", PauseHandler)
+	mux.HandleFunc("POST /api/game/{id}/resume", ResumeHandler)
+	mux.HandleFunc("POST /api/game/{id}/hint", HintHandler)
+	mux.HandleFunc("GET /api/game/{id}/replay", ReplayHandler)
+	mux.HandleFunc("GET /api/game/{id}/gif", GIFHandler)
+	mux.HandleFunc("GET /api/game/{id}/image", ImageHandler)
+	mux.HandleFunc("GET /api/game/{id}/ascii", ASCIIHandler)
+	mux.HandleFunc("GET /api/puzzlebook", PuzzleBookHandler)
+	mux.HandleFunc("POST /api/levels", CreateLevelHandler)
+	mux.HandleFunc("GET /api/levels/{id}", GetLevelHandler)
+	mux.HandleFunc("GET /api/daily", DailyHandler)
+	mux.HandleFunc("GET /api/daily/leaderboard", DailyLeaderboardHandler)
+	mux.HandleFunc("GET /api/leaderboard", LeaderboardHandler)
+
+	// Question bank administration
+	mux.HandleFunc("GET /api/admin/questions", auth.RequireAdmin(ListQuestionsHandler))
+	mux.HandleFunc("POST /api/admin/questions", auth.RequireAdmin(CreateQuestionHandler))
+	mux.HandleFunc("PUT /api/admin/questions/{id}", auth.RequireAdmin(UpdateQuestionHandler))
+	mux.HandleFunc("DELETE /api/admin/questions/{id}", auth.RequireAdmin(DeleteQuestionHandler))
 
 	// WebSocket endpoint
 	mux.HandleFunc("/ws", socket.WebSocketHandler)
+	mux.HandleFunc("/ws/replay", socket.ReplayHandler)
+	mux.HandleFunc("/ws/solve", socket.SolveHandler)
+	mux.HandleFunc("/ws/generate", socket.GenerateHandler)
 
 	return EnableCORS(mux)
 }
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// IsAdmin reports whether the request carries the admin token configured in
+// the ADMIN_TOKEN environment variable, as "Authorization: Bearer <token>".
+// Admin access is disabled entirely when no token is configured.
+func IsAdmin(r *http.Request) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && ValidToken(given)
+}
+
+// ValidToken reports whether a token is the admin token. For clients that
+// cannot set headers, e.g. browser WebSockets passing it in the URL.
+func ValidToken(given string) bool {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// RequireAdmin wraps a handler so it only runs for admin requests.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r) {
+			http.Error(w, "Admin token required", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"math"
+	"maze-game/models"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// answerMatches reports whether an answer is right for a question,
+// the way its type says to compare them.
+func answerMatches(question *models.Question, answer models.Answer) bool {
+	if question.Kind() == models.MultiSelect {
+		return sameSelection(question.CorrectAnswers, answer)
+	}
+	if len(answer) != 1 {
+		return false
+	}
+	given := answer[0]
+
+	switch question.Kind() {
+	case models.TrueFalse:
+		want, err1 := strconv.ParseBool(strings.ToLower(strings.TrimSpace(question.CorrectAns)))
+		got, err2 := strconv.ParseBool(strings.ToLower(strings.TrimSpace(given)))
+		return err1 == nil && err2 == nil && want == got
+	case models.FreeText:
+		got := normalizeText(given)
+		for _, accepted := range append([]string{question.CorrectAns}, question.Aliases...) {
+			want := normalizeText(accepted)
+			if got == want || question.Fuzzy && editDistance(got, want) <= allowedTypos(want) {
+				return true
+			}
+		}
+		return false
+	case models.Numeric:
+		want, err1 := strconv.ParseFloat(strings.TrimSpace(question.CorrectAns), 64)
+		got, err2 := strconv.ParseFloat(strings.TrimSpace(given), 64)
+		return err1 == nil && err2 == nil && math.Abs(got-want) <= question.Tolerance
+	default:
+		return given == question.CorrectAns
+	}
+}
+
+// sameSelection reports whether the selected options are exactly the
+// wanted ones, in any order.
+func sameSelection(want []string, selected models.Answer) bool {
+	got := slices.Compact(slices.Sorted(slices.Values(selected)))
+	return slices.Equal(slices.Sorted(slices.Values(want)), got)
+}
+
+// normalizeText lowercases a text answer and reduces everything that isn't
+// a letter or digit to single spaces, so "  The Eiffel-Tower!" matches
+// "the eiffel tower".
+func normalizeText(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// allowedTypos is how many edits a fuzzy answer may be off by: one per
+// five characters, so short answers must be exact.
+func allowedTypos(answer string) int {
+	return len([]rune(answer)) / 5
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
@@ -0,0 +1,112 @@
+package game
+
+import (
+	"fmt"
+	"maze-game/models"
+	"strings"
+)
+
+// Symbols of the plain-text maze format.
+const (
+	ASCIIWall     = '#'
+	ASCIIPath     = '.'
+	ASCIIStart    = 'S'
+	ASCIIExit     = 'E'
+	ASCIIQuestion = '?'
+	ASCIIGate     = 'G'
+)
+
+// ParseASCII builds a board from the plain-text format, one line per row:
+//
+//	S.#..
+//	.##?#
+//	..G.E
+//
+// Question cells ('?') and question walls ('G') are marked but get their
+// question ID when a game is started from the board.
+func ParseASCII(text string) (models.Board, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	// Ignore trailing blank lines (e.g. the final newline of a file).
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return models.Board{}, fmt.Errorf("maze is empty")
+	}
+
+	rows, cols := len(lines), len(lines[0])
+	if rows > MaxBoardSize || cols > MaxBoardSize {
+		return models.Board{}, fmt.Errorf("maze is %d x %d, at most %d x %d is allowed", cols, rows, MaxBoardSize, MaxBoardSize)
+	}
+	starts, exits := 0, 0
+	grid := make([][]models.Cell, rows)
+
+	for y, line := range lines {
+		if len(line) != cols {
+			return models.Board{}, fmt.Errorf("line %d: expected %d columns, got %d", y+1, cols, len(line))
+		}
+		grid[y] = make([]models.Cell, cols)
+		for x := 0; x < cols; x++ {
+			cell := models.Cell{Position: models.Position{X: x, Y: y}}
+			switch line[x] {
+			case ASCIIWall:
+				cell.Type = models.Wall
+			case ASCIIPath:
+				cell.Type = models.Path
+			case ASCIIStart:
+				cell.Type = models.Start
+				starts++
+			case ASCIIExit:
+				cell.Type = models.End
+				exits++
+			case ASCIIQuestion:
+				cell.Type = models.Path
+				cell.HasQuestion = true
+			case ASCIIGate:
+				cell.Type = models.Wall
+				cell.IsQuestionWall = true
+			default:
+				return models.Board{}, fmt.Errorf("line %d, column %d: unknown symbol %q", y+1, x+1, line[x])
+			}
+			grid[y][x] = cell
+		}
+	}
+
+	if starts != 1 {
+		return models.Board{}, fmt.Errorf("maze needs exactly one start (%c), found %d", ASCIIStart, starts)
+	}
+	if exits == 0 {
+		return models.Board{}, fmt.Errorf("maze needs an exit (%c)", ASCIIExit)
+	}
+
+	return models.Board{Rows: rows, Cols: cols, Grid: grid}, nil
+}
+
+// FormatASCII serializes a board to the plain-text format read by ParseASCII.
+func FormatASCII(board models.Board) string {
+	var b strings.Builder
+	b.Grow(board.Rows * (board.Cols + 1))
+
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			cell := board.Grid[y][x]
+			switch {
+			case cell.Type == models.Start:
+				b.WriteByte(ASCIIStart)
+			case cell.Type == models.End:
+				b.WriteByte(ASCIIExit)
+			case cell.IsQuestionWall:
+				b.WriteByte(ASCIIGate)
+			case cell.Type == models.Wall || cell.Type == models.Beyond:
+				b.WriteByte(ASCIIWall)
+			case cell.HasQuestion:
+				b.WriteByte(ASCIIQuestion)
+			default:
+				b.WriteByte(ASCIIPath)
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
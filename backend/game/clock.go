@@ -0,0 +1,169 @@
+package game
+
+import (
+	"maze-game/models"
+	"sync"
+	"time"
+)
+
+// Reasons a game was lost.
+const (
+	ReasonTimeout = "TIMEOUT"
+	ReasonNoLives = "NO_LIVES"
+)
+
+// Time-attack budget: a base plus a bit per step of the shortest route.
+const (
+	timeAttackBase    = 10 * time.Second
+	timeAttackPerStep = time.Second
+)
+
+var (
+	timersMu sync.Mutex
+	// Game ID -> timer that ends the game when its time limit is hit.
+	timers = make(map[string]*time.Timer)
+)
+
+// SetTimeLimit gives a game a time limit, enforced by the server even if
+// the player never sends another move.
+func SetTimeLimit(game *models.GameState, limit time.Duration) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	game.TimeLimitMs = limit.Milliseconds()
+	refreshClock(game)
+	if game.Status == "ACTIVE" {
+		armTimeout(game)
+	}
+}
+
+// timeAttackLimit is the default limit for ModeTimeAttack.
+func timeAttackLimit(board models.Board) time.Duration {
+	return timeAttackBase + time.Duration(len(SolveMaze(board)))*timeAttackPerStep
+}
+
+// armTimer (re)starts the timer of a game, replacing any earlier one.
+// fn runs with stateMu held. Caller holds stateMu.
+func armTimer(game *models.GameState, after time.Duration, fn func()) {
+	timersMu.Lock()
+	defer timersMu.Unlock()
+
+	if t, ok := timers[game.ID]; ok {
+		t.Stop()
+	}
+	var t *time.Timer
+	t = time.AfterFunc(max(after, 0), func() {
+		stateMu.Lock()
+		defer stateMu.Unlock()
+
+		// A replaced timer may already be waiting for the lock; skip it.
+		timersMu.Lock()
+		current := timers[game.ID] == t
+		timersMu.Unlock()
+		if current {
+			fn()
+		}
+	})
+	timers[game.ID] = t
+}
+
+// armTimeout arms the timer that ends the game when its time runs out.
+// Caller holds stateMu.
+func armTimeout(game *models.GameState) {
+	if game.TimeLimitMs == 0 {
+		stopTimer(game)
+		return
+	}
+	remaining := time.Duration(game.TimeLimitMs)*time.Millisecond - Elapsed(game)
+	armTimer(game, remaining, func() { checkTimeout(game) })
+}
+
+// stopTimer drops the timeout timer of a game, if any.
+func stopTimer(game *models.GameState) {
+	timersMu.Lock()
+	defer timersMu.Unlock()
+
+	if t, ok := timers[game.ID]; ok {
+		t.Stop()
+		delete(timers, game.ID)
+	}
+}
+
+// Elapsed returns how long the game has been played. The clock stops
+// while the game is paused and when it ends.
+func Elapsed(game *models.GameState) time.Duration {
+	end := time.Now()
+	if game.FinishedAt != nil {
+		end = *game.FinishedAt
+	}
+	if game.PausedAt != nil {
+		end = *game.PausedAt
+	}
+	return end.Sub(game.StartedAt) - time.Duration(game.PausedMs)*time.Millisecond
+}
+
+// RefreshClock updates the clock fields of a game before it is sent to a
+// client and ends it if its time ran out.
+func RefreshClock(game *models.GameState) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	checkTimeout(game)
+}
+
+// Snapshot returns an up-to-date copy of a game that is safe to serialize
+// while the game keeps changing.
+func Snapshot(game *models.GameState) *models.GameState {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	checkTimeout(game)
+	return snapshot(game)
+}
+
+// Finished reports whether a game is over, won or lost. A paused game is
+// not, it can still be resumed.
+func Finished(game *models.GameState) bool {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	checkTimeout(game)
+	return game.Status == "WON" || game.Status == "LOST"
+}
+
+// SnapshotBoard returns a copy of a game's board that is safe to read
+// while the game goes on.
+func SnapshotBoard(game *models.GameState) models.Board {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return copyBoard(game.Board)
+}
+
+// snapshot copies a game, board included, since answers open gates and
+// clear question cells in place. Caller holds stateMu.
+func snapshot(game *models.GameState) *models.GameState {
+	s := *game
+	s.Board = copyBoard(game.Board)
+	s.History = nil
+	s.Visited = nil
+	s.ScoreBreakdown = append([]models.ScoreItem(nil), game.ScoreBreakdown...)
+	return &s
+}
+
+// checkTimeout refreshes the clock and ends the game once the time limit
+// is hit. Caller holds stateMu.
+func checkTimeout(game *models.GameState) bool {
+	refreshClock(game)
+	if game.Status != "ACTIVE" || game.TimeLimitMs == 0 || *game.TimeRemainingMs > 0 {
+		return false
+	}
+	endGame(game, "LOST", ReasonTimeout)
+	refreshClock(game)
+	return true
+}
+
+// refreshClock recomputes ElapsedMs and TimeRemainingMs.
+func refreshClock(game *models.GameState) {
+	game.ElapsedMs = Elapsed(game).Milliseconds()
+	if game.TimeLimitMs > 0 {
+		remaining := max(game.TimeLimitMs-game.ElapsedMs, 0)
+		game.TimeRemainingMs = &remaining
+	}
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Game modes.
+const (
+	ModeClassic    = "classic"
+	ModeDaily      = "daily"
+	ModeTimeAttack = "time_attack"
+	ModeQuiz       = "quiz" // Questions on the way, see DefaultQuestionDensity
+)
+
+// Tables used to encode share codes. Only ever append to them, otherwise
+// existing codes change meaning.
+var (
+	codeAlgorithms = []string{AlgorithmBacktracking, AlgorithmPrim}
+	codeModes      = []string{ModeClassic, ModeDaily, ModeTimeAttack, ModeQuiz}
+)
+
+// Current share code format.
+const codeVersion = 3
+
+// Bytes after the seed in each code version. Older versions lack the
+// trailing fields, which then default to 0.
+var codeTrailer = map[byte]int{
+	1: 3, // algorithm, braiding, mode
+	2: 4, // + question density
+	3: 5, // + question walls
+}
+
+// GameOptions is everything needed to rebuild a game's maze.
+type GameOptions struct {
+	Maze MazeOptions
+	Mode string
+	// Share of path cells with a question, 0 to MaxQuestionDensity.
+	QuestionDensity float64
+	// Question walls on the route to the exit, 0 to MaxQuestionWalls.
+	QuestionWalls int
+}
+
+// ValidMode reports whether name is a known game mode.
+func ValidMode(name string) bool {
+	return name == "" || indexOf(codeModes, name) >= 0
+}
+
+// EncodeCode packs game options into a short URL-safe code.
+// Layout: version, rows, cols, seed, algorithm, braiding (percent), mode,
+// question density (percent), question walls.
+func EncodeCode(opts GameOptions) string {
+	buf := make([]byte, 0, 32)
+	buf = append(buf, codeVersion)
+	buf = binary.AppendUvarint(buf, uint64(opts.Maze.Rows))
+	buf = binary.AppendUvarint(buf, uint64(opts.Maze.Cols))
+	buf = binary.AppendVarint(buf, opts.Maze.Seed)
+	buf = append(buf, byte(max(indexOf(codeAlgorithms, opts.Maze.Algorithm), 0)))
+	buf = append(buf, byte(math.Round(opts.Maze.Braiding*100)))
+	buf = append(buf, byte(max(indexOf(codeModes, opts.Mode), 0)))
+	buf = append(buf, byte(math.Round(opts.QuestionDensity*100)))
+	buf = append(buf, byte(opts.QuestionWalls))
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodeCode unpacks a code created by EncodeCode.
+func DecodeCode(code string) (GameOptions, error) {
+	var opts GameOptions
+	invalid := fmt.Errorf("invalid maze code")
+
+	buf, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil || len(buf) == 0 {
+		return opts, invalid
+	}
+	version := buf[0]
+	trailer, ok := codeTrailer[version]
+	if !ok {
+		return opts, fmt.Errorf("unsupported maze code version %d", version)
+	}
+	buf = buf[1:]
+
+	rows, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return opts, invalid
+	}
+	buf = buf[n:]
+	cols, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return opts, invalid
+	}
+	buf = buf[n:]
+	seed, n := binary.Varint(buf)
+	if n <= 0 {
+		return opts, invalid
+	}
+	buf = buf[n:]
+	if len(buf) != trailer {
+		return opts, invalid
+	}
+	buf = append(buf, make([]byte, codeTrailer[codeVersion]-trailer)...)
+	algorithm, braiding, mode, density, walls := int(buf[0]), int(buf[1]), int(buf[2]), int(buf[3]), int(buf[4])
+
+	if rows == 0 || cols == 0 || rows > MaxBoardSize || cols > MaxBoardSize || seed == 0 ||
+		algorithm >= len(codeAlgorithms) || braiding > 100 || mode >= len(codeModes) ||
+		float64(density) > MaxQuestionDensity*100 || walls > MaxQuestionWalls {
+		return opts, invalid
+	}
+
+	opts.Maze = MazeOptions{
+		Rows:      int(rows),
+		Cols:      int(cols),
+		Seed:      seed,
+		Algorithm: codeAlgorithms[algorithm],
+		Braiding:  float64(braiding) / 100,
+	}
+	opts.Mode = codeModes[mode]
+	opts.QuestionDensity = float64(density) / 100
+	opts.QuestionWalls = walls
+	return opts, nil
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maze-game/models"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Settings of the daily challenge maze. Only the seed changes per day.
+const (
+	dailyRows       = 31
+	dailyCols       = 31
+	dailyAlgorithm  = AlgorithmBacktracking
+	dailyDateFormat = "2006-01-02"
+)
+
+// DailyChallenge describes the maze everyone plays on a given UTC date.
+type DailyChallenge struct {
+	Date      string  `json:"date"`
+	Seed      int64   `json:"seed"`
+	Rows      int     `json:"rows"`
+	Cols      int     `json:"cols"`
+	Algorithm string  `json:"algorithm"`
+	Braiding  float64 `json:"braiding"`
+	Code      string  `json:"code"`
+}
+
+var (
+	dailyMu sync.Mutex
+	// Date -> normalized player name, for the one scored attempt per day.
+	dailyAttempts = make(map[string]map[string]bool)
+)
+
+// Daily returns the challenge for the UTC date of t.
+func Daily(t time.Time) DailyChallenge {
+	date := t.UTC().Format(dailyDateFormat)
+
+	// Seed from the date so every server agrees without sharing state.
+	h := fnv.New32a()
+	h.Write([]byte(date))
+	seed := int64(h.Sum32()>>1) + 1
+
+	opts := dailyOptions(seed)
+	return DailyChallenge{
+		Date:      date,
+		Seed:      seed,
+		Rows:      opts.Maze.Rows,
+		Cols:      opts.Maze.Cols,
+		Algorithm: opts.Maze.Algorithm,
+		Braiding:  opts.Maze.Braiding,
+		Code:      EncodeCode(opts),
+	}
+}
+
+func dailyOptions(seed int64) GameOptions {
+	return GameOptions{
+		Maze: MazeOptions{
+			Rows:      dailyRows,
+			Cols:      dailyCols,
+			Seed:      seed,
+			Algorithm: dailyAlgorithm,
+			Braiding:  DefaultBraiding,
+		},
+		Mode: ModeDaily,
+	}
+}
+
+// NewDailyGame starts today's challenge for a player. Only the first
+// attempt of the day is ranked; later ones are practice runs.
+func NewDailyGame(playerName string) (*models.GameState, error) {
+	key := normalizeName(playerName)
+	if key == "" {
+		return nil, fmt.Errorf("player_name is required for the daily challenge")
+	}
+
+	daily := Daily(time.Now())
+
+	dailyMu.Lock()
+	if dailyAttempts[daily.Date] == nil {
+		dailyAttempts[daily.Date] = make(map[string]bool)
+	}
+	ranked := !dailyAttempts[daily.Date][key]
+	dailyAttempts[daily.Date][key] = true
+	dailyMu.Unlock()
+
+	gameState := NewGameWithOptions(dailyOptions(daily.Seed))
+	gameState.Player.Name = strings.TrimSpace(playerName)
+	gameState.Ranked = ranked
+
+	return gameState, nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"fmt"
+	"maze-game/models"
+	"maze-game/pathfind"
+)
+
+// MaxHintCells caps how far ahead a single hint shows the route.
+const MaxHintCells = 10
+
+// RuleHint is the breakdown entry for points spent on hints.
+const RuleHint = "hint"
+
+// Hint points the player toward the nearest exit.
+type Hint struct {
+	Direction string            `json:"direction"` // Next move: "UP", "DOWN", "LEFT" or "RIGHT"
+	Cells     []models.Position `json:"cells"`     // The next cells of the route, in order
+	Distance  int               `json:"distance"`  // Moves left to the exit
+}
+
+// GetHint spends a hint and returns the next cells (up to MaxHintCells) on
+// the shortest route from the player to an exit. The cost in points and
+// lives comes from the game's scoring rules.
+func GetHint(game *models.GameState, cells int) (*Hint, error) {
+	cells = min(max(cells, 1), MaxHintCells)
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if err := checkPlayable(game); err != nil {
+		return nil, err
+	}
+
+	rules := RulesFor(game.Mode)
+	// A hint is never allowed to end the game.
+	if rules.HintLifeCost > 0 && game.Player.Lives <= rules.HintLifeCost {
+		return nil, fmt.Errorf("not enough lives for a hint")
+	}
+
+	path := findPath(game.Board, game.Player.CurrentPos)
+	if len(path) < 2 {
+		return nil, fmt.Errorf("no route to the exit")
+	}
+
+	game.Player.HintsUsed++
+	game.Player.Lives -= rules.HintLifeCost
+	award(game, RuleHint, -rules.HintCost)
+
+	return &Hint{
+		Direction: directionTo(path[0], path[1]),
+		Cells:     path[1:min(len(path), cells+1)],
+		Distance:  len(path) - 1,
+	}, nil
+}
+
+// directionTo names the move from one cell to its neighbour.
+func directionTo(from, to models.Position) string {
+	switch {
+	case to.Y < from.Y:
+		return "UP"
+	case to.Y > from.Y:
+		return "DOWN"
+	case to.X < from.X:
+		return "LEFT"
+	default:
+		return "RIGHT"
+	}
+}
+
+// findPath runs A* from a cell to the nearest exit, with the Manhattan
+// distance to the closest exit as heuristic. It returns the route with both
+// ends included, or nil if no exit can be reached.
+func findPath(board models.Board, from models.Position) []models.Position {
+	path, _ := pathfind.AStar(pathfind.New(board), from, pathfind.IsType(board, models.End),
+		pathfind.Manhattan(pathfind.Find(board, models.End)...))
+	return path
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"maze-game/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Paging limits for leaderboard queries.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// LeaderboardEntry is a single won game.
+type LeaderboardEntry struct {
+	Rank       int       `json:"rank"`
+	GameID     string    `json:"game_id"`
+	PlayerName string    `json:"player_name"`
+	Mode       string    `json:"mode"`
+	Code       string    `json:"code,omitempty"`
+	Seed       int64     `json:"seed"`
+	Rows       int       `json:"rows"`
+	Cols       int       `json:"cols"`
+	Moves      int       `json:"moves"`
+	TimeMs     int64     `json:"time_ms"`
+	Score      int       `json:"score"`
+	HintsUsed  int       `json:"hints_used"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// LeaderboardQuery filters and pages a leaderboard. Zero values match
+// everything.
+type LeaderboardQuery struct {
+	Seed     int64
+	Rows     int
+	Cols     int
+	Code     string
+	Mode     string
+	Page     int // 1-based
+	PageSize int
+}
+
+var (
+	leaderboardMu sync.RWMutex
+	// Every ranked win, in the order they finished.
+	finishedGames []LeaderboardEntry
+)
+
+// recordResult stores a won game and returns its rank among games on the
+// same maze, i.e. with the same share code.
+func recordResult(game *models.GameState) int {
+	entry := LeaderboardEntry{
+		GameID:     game.ID,
+		PlayerName: game.Player.Name,
+		Mode:       game.Mode,
+		Code:       game.Code,
+		Seed:       game.Board.Seed,
+		Rows:       game.Board.Rows,
+		Cols:       game.Board.Cols,
+		Moves:      game.Player.Moves,
+		TimeMs:     Elapsed(game).Milliseconds(),
+		Score:      game.Player.Score,
+		HintsUsed:  game.Player.HintsUsed,
+		StartedAt:  game.StartedAt,
+		FinishedAt: *game.FinishedAt,
+	}
+	if entry.PlayerName == "" {
+		entry.PlayerName = "Anonymous"
+	}
+
+	leaderboardMu.Lock()
+	finishedGames = append(finishedGames, entry)
+	leaderboardMu.Unlock()
+
+	entries, _ := Leaderboard(LeaderboardQuery{Code: entry.Code, PageSize: -1})
+	for _, e := range entries {
+		if e.GameID == entry.GameID {
+			return e.Rank
+		}
+	}
+	return 0
+}
+
+// Leaderboard returns one page of matching entries, best score first
+// (fewest moves, then fastest time break ties), and the total number of
+// matches. A negative PageSize returns everything.
+func Leaderboard(q LeaderboardQuery) ([]LeaderboardEntry, int) {
+	entries := filterEntries(func(e LeaderboardEntry) bool {
+		return (q.Seed == 0 || e.Seed == q.Seed) &&
+			(q.Rows == 0 || e.Rows == q.Rows) &&
+			(q.Cols == 0 || e.Cols == q.Cols) &&
+			(q.Code == "" || e.Code == q.Code) &&
+			(q.Mode == "" || e.Mode == q.Mode)
+	})
+	sortEntries(entries, func(a, b LeaderboardEntry) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Moves != b.Moves {
+			return a.Moves < b.Moves
+		}
+		return a.TimeMs < b.TimeMs
+	})
+
+	return paginate(entries, q.Page, q.PageSize), len(entries)
+}
+
+// DailyLeaderboard returns the ranked daily wins for a date (YYYY-MM-DD),
+// fewest moves first and fastest time breaking ties. The day is the one
+// the game was started on, so a run past midnight still counts for the
+// maze it was played on.
+func DailyLeaderboard(date string) []LeaderboardEntry {
+	entries := filterEntries(func(e LeaderboardEntry) bool {
+		return e.Mode == ModeDaily && e.StartedAt.UTC().Format(dailyDateFormat) == date
+	})
+	sortEntries(entries, func(a, b LeaderboardEntry) bool {
+		if a.Moves != b.Moves {
+			return a.Moves < b.Moves
+		}
+		return a.TimeMs < b.TimeMs
+	})
+	return entries
+}
+
+func filterEntries(keep func(LeaderboardEntry) bool) []LeaderboardEntry {
+	leaderboardMu.RLock()
+	defer leaderboardMu.RUnlock()
+
+	entries := []LeaderboardEntry{}
+	for _, e := range finishedGames {
+		if keep(e) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// sortEntries sorts by less and numbers the entries from 1.
+func sortEntries(entries []LeaderboardEntry, less func(a, b LeaderboardEntry) bool) {
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+}
+
+func paginate(entries []LeaderboardEntry, page, pageSize int) []LeaderboardEntry {
+	if pageSize < 0 {
+		return entries
+	}
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	pageSize = min(pageSize, MaxPageSize)
+	page = max(page, 1)
+
+	// Compare before multiplying, a huge page would overflow.
+	if page-1 > len(entries)/pageSize {
+		return []LeaderboardEntry{}
+	}
+	start := (page - 1) * pageSize
+	if start >= len(entries) {
+		return []LeaderboardEntry{}
+	}
+	return entries[start:min(start+pageSize, len(entries))]
+}
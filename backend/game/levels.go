@@ -0,0 +1,216 @@
+package game
+
+import (
+	"fmt"
+	"maze-game/models"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Special cell types accepted in a level definition.
+const (
+	SpecialQuestionWall = "QUESTION_WALL"
+	SpecialBeyond       = "BEYOND"
+)
+
+// Level is a hand-crafted board stored on the server.
+type Level struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Board     models.Board `json:"board"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// LevelQuestion places a question on a path cell of a level.
+type LevelQuestion struct {
+	X          int `json:"x"`
+	Y          int `json:"y"`
+	QuestionID int `json:"question_id"`
+}
+
+// LevelSpecial places a special cell on a level.
+type LevelSpecial struct {
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Type       string `json:"type"`                  // SpecialQuestionWall or SpecialBeyond
+	QuestionID int    `json:"question_id,omitempty"` // Required for question walls
+}
+
+// LevelDefinition is the JSON form of a level. Every cell that is not
+// listed as a wall or special cell is a path.
+type LevelDefinition struct {
+	Name      string            `json:"name"`
+	Rows      int               `json:"rows"`
+	Cols      int               `json:"cols"`
+	Walls     []models.Position `json:"walls"`
+	Start     models.Position   `json:"start"`
+	Exit      models.Position   `json:"exit"`
+	Questions []LevelQuestion   `json:"questions"`
+	Special   []LevelSpecial    `json:"special"`
+}
+
+var (
+	levelsMu sync.RWMutex
+	levels   = make(map[string]*Level)
+)
+
+// BuildLevelBoard turns a JSON definition into a board. It returns every
+// placement problem it finds (out of bounds, overlapping cells, ...).
+func BuildLevelBoard(def LevelDefinition) (models.Board, []string) {
+	if def.Rows <= 0 || def.Cols <= 0 || def.Rows > MaxBoardSize || def.Cols > MaxBoardSize {
+		return models.Board{}, []string{fmt.Sprintf("rows and cols must be between 1 and %d", MaxBoardSize)}
+	}
+
+	var problems []string
+	inBounds := func(what string, x, y int) bool {
+		if x < 0 || x >= def.Cols || y < 0 || y >= def.Rows {
+			problems = append(problems, fmt.Sprintf("%s at (%d,%d) is out of bounds", what, x, y))
+			return false
+		}
+		return true
+	}
+
+	grid := make([][]models.Cell, def.Rows)
+	for y := range grid {
+		grid[y] = make([]models.Cell, def.Cols)
+		for x := range grid[y] {
+			grid[y][x] = models.Cell{Type: models.Path, Position: models.Position{X: x, Y: y}}
+		}
+	}
+
+	for i, w := range def.Walls {
+		if inBounds(fmt.Sprintf("walls[%d]", i), w.X, w.Y) {
+			grid[w.Y][w.X].Type = models.Wall
+		}
+	}
+
+	for i, s := range def.Special {
+		what := fmt.Sprintf("special[%d]", i)
+		if !inBounds(what, s.X, s.Y) {
+			continue
+		}
+		cell := &grid[s.Y][s.X]
+		switch s.Type {
+		case SpecialQuestionWall:
+			cell.Type = models.Wall
+			cell.IsQuestionWall = true
+			cell.QuestionID = s.QuestionID
+		case SpecialBeyond:
+			cell.Type = models.Beyond
+		default:
+			problems = append(problems, fmt.Sprintf("%s has unknown type %q", what, s.Type))
+		}
+	}
+
+	for i, q := range def.Questions {
+		what := fmt.Sprintf("questions[%d]", i)
+		if !inBounds(what, q.X, q.Y) {
+			continue
+		}
+		cell := &grid[q.Y][q.X]
+		if cell.Type != models.Path {
+			problems = append(problems, fmt.Sprintf("%s at (%d,%d) is not on a path", what, q.X, q.Y))
+			continue
+		}
+		cell.HasQuestion = true
+		cell.QuestionID = q.QuestionID
+	}
+
+	// Start and exit go last and must land on plain path cells.
+	place := func(what string, p models.Position, t models.CellType) {
+		if !inBounds(what, p.X, p.Y) {
+			return
+		}
+		cell := &grid[p.Y][p.X]
+		if cell.Type != models.Path || cell.HasQuestion {
+			problems = append(problems, fmt.Sprintf("%s at (%d,%d) overlaps a %s cell", what, p.X, p.Y, cell.Type))
+			return
+		}
+		cell.Type = t
+	}
+	place("start", def.Start, models.Start)
+	place("exit", def.Exit, models.End)
+
+	return models.Board{Rows: def.Rows, Cols: def.Cols, Grid: grid}, problems
+}
+
+// ValidateBoard checks a hand-made board structurally: exactly one start,
+// an exit that can be reached from it and question IDs that exist.
+func ValidateBoard(board models.Board) []string {
+	var problems []string
+
+	starts, exits := 0, 0
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			cell := board.Grid[y][x]
+			switch cell.Type {
+			case models.Start:
+				starts++
+			case models.End:
+				exits++
+			}
+
+			// Question cells and walls without an ID (e.g. '?' and 'G' in ASCII) are filled in at game start.
+			if cell.IsQuestionWall && cell.QuestionID != 0 && !questionExists(cell.QuestionID) {
+				problems = append(problems, fmt.Sprintf("question wall at (%d,%d) has unknown question_id %d", x, y, cell.QuestionID))
+			}
+			if cell.HasQuestion && cell.QuestionID != 0 && !questionExists(cell.QuestionID) {
+				problems = append(problems, fmt.Sprintf("question at (%d,%d) has unknown question_id %d", x, y, cell.QuestionID))
+			}
+		}
+	}
+
+	if starts != 1 {
+		problems = append(problems, fmt.Sprintf("level needs exactly one start, found %d", starts))
+	}
+	if exits == 0 {
+		problems = append(problems, "level needs an exit")
+	}
+	if starts == 1 && exits > 0 && SolveMaze(board) == nil {
+		problems = append(problems, "no exit can be reached from the start")
+	}
+
+	return problems
+}
+
+// SaveLevel stores a validated board and returns the new level.
+func SaveLevel(name string, board models.Board) *Level {
+	level := &Level{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Board:     board,
+		CreatedAt: time.Now(),
+	}
+
+	levelsMu.Lock()
+	levels[level.ID] = level
+	levelsMu.Unlock()
+
+	return level
+}
+
+// GetLevel retrieves a stored level by ID.
+func GetLevel(id string) (*Level, bool) {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	level, exists := levels[id]
+	return level, exists
+}
+
+// NewGameFromLevel starts a game on a copy of a stored level, so the game
+// can change its board without touching the level.
+func NewGameFromLevel(level *Level) *models.GameState {
+	return NewGameFromBoard(copyBoard(level.Board))
+}
+
+// copyBoard returns a board with its own grid.
+func copyBoard(board models.Board) models.Board {
+	grid := make([][]models.Cell, len(board.Grid))
+	for y, row := range board.Grid {
+		grid[y] = append([]models.Cell(nil), row...)
+	}
+	board.Grid = grid
+	return board
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"math/rand"
 	"maze-game/models"
-	"maze-game/store"
+	"maze-game/pathfind"
 	"time"
 )
 
 type AnswerResult struct {
-	Correct   bool              `json:"correct"`
+	Correct bool `json:"correct"`
+	// The question wall a correct answer opened, if any.
+	Opened    *models.Position  `json:"opened,omitempty"`
 	GameState *models.GameState `json:"game_state"`
 }
 
@@ -18,12 +20,98 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-// Function to generate a new maze.
-// Algorithm: Prim's Algorithm or Recursive Backtracking.
+// Maze generation algorithms.
+const (
+	AlgorithmBacktracking = "backtracking"
+	AlgorithmPrim         = "prim"
+)
+
+// DefaultBraiding is the share of dead ends opened into loops by default.
+const DefaultBraiding = 0.5
+
+// MaxBoardSize is the largest board we build in either dimension.
+const MaxBoardSize = 500
+
+// MazeOptions controls how a maze is generated.
+type MazeOptions struct {
+	Rows      int
+	Cols      int
+	Seed      int64   // Same seed + options = same maze. 0 picks a random seed.
+	Algorithm string  // AlgorithmBacktracking (default) or AlgorithmPrim.
+	Braiding  float64 // 0 = perfect maze, 1 = open every dead end we can.
+	// OnStep, if set, is called for every change to the grid while the
+	// maze is built, in order. Not part of the share code.
+	OnStep func(GenerationStep)
+}
+
+// Phases of maze generation, as reported in a GenerationStep.
+const (
+	PhaseCarve = "carve" // A generator opened a passage
+	PhaseBraid = "braid" // A dead end was opened into a loop
+	PhaseStart = "start" // The start cell was placed
+	PhaseExit  = "exit"  // The exit was placed, plus any cell opened to reach it
+)
+
+// GenerationStep is one change to the grid during generation: the cells
+// that turned into Type, in order.
+type GenerationStep struct {
+	Phase string            `json:"phase"`
+	Type  models.CellType   `json:"type"`
+	Cells []models.Position `json:"cells"`
+}
+
+// stepRecorder reports grid changes to MazeOptions.OnStep.
+type stepRecorder func(phase string, t models.CellType, cells ...models.Position)
+
+// RandomSeed picks a fresh non-zero seed. Seeds stay within 32 bits so
+// share codes are short.
+func RandomSeed() int64 {
+	return int64(rand.Uint32()>>1) + 1
+}
+
+// ValidAlgorithm reports whether name is a known generation algorithm.
+func ValidAlgorithm(name string) bool {
+	return name == "" || name == AlgorithmBacktracking || name == AlgorithmPrim
+}
+
+// BraidingFor maps a difficulty name to a braiding factor.
+// More loops = harder, since the player can't just follow a wall.
+func BraidingFor(difficulty string) (float64, bool) {
+	switch difficulty {
+	case "easy":
+		return 0.1, true
+	case "", "medium":
+		return DefaultBraiding, true
+	case "hard":
+		return 0.9, true
+	}
+	return 0, false
+}
+
 // Function to generate a new maze.
 // Algorithm: Standard Recursive Backtracking to create a dense labyrinth.
 func GenerateMaze(rows, cols int) models.Board {
-	fmt.Println("Generating standard maze", rows, "x", cols)
+	return GenerateMazeWithOptions(MazeOptions{Rows: rows, Cols: cols, Braiding: DefaultBraiding})
+}
+
+// GenerateMazeWithOptions generates a maze from a seed, so the same options
+// always produce the same board.
+func GenerateMazeWithOptions(opts MazeOptions) models.Board {
+	rows, cols := opts.Rows, opts.Cols
+	if opts.Seed == 0 {
+		opts.Seed = RandomSeed()
+	}
+	if opts.Algorithm == "" {
+		opts.Algorithm = AlgorithmBacktracking
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	record := func(phase string, t models.CellType, cells ...models.Position) {
+		if opts.OnStep != nil {
+			opts.OnStep(GenerationStep{Phase: phase, Type: t, Cells: cells})
+		}
+	}
+
+	fmt.Println("Generating", opts.Algorithm, "maze", rows, "x", cols, "seed", opts.Seed)
 
 	// Ensure odd dimensions for proper wall/path generation if using "jump 2" method.
 	// adjustments might be needed if inputs are even, but let's try to handle it.
@@ -42,41 +130,14 @@ func GenerateMaze(rows, cols int) models.Board {
 		}
 	}
 
-	// 2. DFS Carving (Recursive Backtracking)
-	// We carve paths.
-	// Start at 0,0
-
-	var carve func(cx, cy int)
-	carve = func(cx, cy int) {
-		grid[cy][cx].Type = models.Path
-
-		// Directions: Up, Down, Left, Right (Jump 2 cells)
-		dirs := [][]int{
-			{0, -2, 0, -1}, // dx, dy, midX, midY relative
-			{0, 2, 0, 1},
-			{-2, 0, -1, 0},
-			{2, 0, 1, 0},
-		}
-		rand.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
-
-		for _, d := range dirs {
-			nx, ny := cx+d[0], cy+d[1]
-			mx, my := cx+d[2], cy+d[3] // Wall between
-
-			// Check bounds for target
-			if nx >= 0 && nx < cols && ny >= 0 && ny < rows {
-				if grid[ny][nx].Type == models.Wall {
-					// Carve through wall
-					grid[my][mx].Type = models.Path
-					carve(nx, ny)
-				}
-			}
-		}
+	// 2. Carve paths, starting at 0,0
+	switch opts.Algorithm {
+	case AlgorithmPrim:
+		carvePrim(grid, rng, record)
+	default:
+		carveBacktracking(grid, rng, record)
 	}
 
-	// Start carving
-	carve(0, 0)
-
 	// 2.5. Braiding (Remove Dead Ends to create Loops)
 	// "User must be confused too which way to go :/"
 	// A perfect maze has no loops. Adding loops makes it harder (can't just follow walls).
@@ -84,7 +145,7 @@ func GenerateMaze(rows, cols int) models.Board {
 
 	// We iterate internally to find dead ends and open them up.
 	// High braidingFactor = more loops = harder/more confusing.
-	braidingFactor := 0.5 // 50% of dead ends turned into loops
+	braidingFactor := opts.Braiding
 
 	type Point struct{ X, Y int }
 
@@ -109,7 +170,7 @@ func GenerateMaze(rows, cols int) models.Board {
 				neighbors := getPathNeighbors(x, y)
 				if len(neighbors) == 1 {
 					// DEAD END FOUND!
-					if rand.Float64() < braidingFactor {
+					if rng.Float64() < braidingFactor {
 						// Try to connect to another path nearby (jump 2)
 						// Check 4 directions for a wall that separates us from another path
 						dirs := [][]int{
@@ -118,7 +179,7 @@ func GenerateMaze(rows, cols int) models.Board {
 							{-2, 0, -1, 0},
 							{2, 0, 1, 0},
 						}
-						rand.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+						rng.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
 
 						connected := false
 						for _, d := range dirs {
@@ -130,6 +191,7 @@ func GenerateMaze(rows, cols int) models.Board {
 								// Actually, if it's a path, it's a valid candidate.
 								if grid[ny][nx].Type == models.Path {
 									grid[my][mx].Type = models.Path
+									record(PhaseBraid, models.Path, models.Position{X: mx, Y: my})
 									connected = true
 									break
 								}
@@ -150,6 +212,7 @@ func GenerateMaze(rows, cols int) models.Board {
 	// Ensure Start and End are open (Backtracking usually guarantees coverage of reachable areas)
 	// Force Start
 	grid[0][0].Type = models.Start
+	record(PhaseStart, models.Start, models.Position{X: 0, Y: 0})
 
 	// Force End at bottom-right (or closest valid path cell?)
 	// In odd-grid generation, usually the last cell might be a wall if dimensions are even.
@@ -181,35 +244,165 @@ func GenerateMaze(rows, cols int) models.Board {
 			// Force connection to a neighbor (e.g. Up)
 			if endY > 0 {
 				grid[endY-1][endX].Type = models.Path
+				record(PhaseExit, models.Path, models.Position{X: endX, Y: endY - 1})
 			}
 		}
 	} else {
 		grid[endY][endX].Type = models.End
 	}
+	record(PhaseExit, models.End, models.Position{X: endX, Y: endY})
 
-	// 3. Add Questions to Path
-	// [REMOVED] User requested removal of questions.
-	/*
-	   for y := 0; y < rows; y++ {
-	       for x := 0; x < cols; x++ {
-	           if grid[y][x].Type == models.Path {
-	               // 5% chance (lower density for large maze)
-	               if rand.Float32() < 0.05 {
-	                   grid[y][x].HasQuestion = true
-	                   grid[y][x].QuestionID = store.QuestionBank[rand.Intn(len(store.QuestionBank))].ID
-	               }
-	           }
-	       }
-	   }
-	*/
+	// 3. Questions are placed per game, see placeQuestions.
 
 	// No "Question Wall" or "Beyond" logic needed for Standard Maze.
 
-	return models.Board{Grid: grid, Rows: rows, Cols: cols}
+	return models.Board{Grid: grid, Rows: rows, Cols: cols, Seed: opts.Seed}
+}
+
+// jumps are the 2-step moves used when carving: dx, dy, midX, midY relative.
+var jumps = [][]int{
+	{0, -2, 0, -1},
+	{0, 2, 0, 1},
+	{-2, 0, -1, 0},
+	{2, 0, 1, 0},
+}
+
+// carveBacktracking carves the grid with DFS (Recursive Backtracking).
+// Long winding corridors, few branches.
+func carveBacktracking(grid [][]models.Cell, rng *rand.Rand, record stepRecorder) {
+	rows, cols := len(grid), len(grid[0])
+
+	var carve func(cx, cy int)
+	carve = func(cx, cy int) {
+		grid[cy][cx].Type = models.Path
+
+		// Directions: Up, Down, Left, Right (Jump 2 cells)
+		dirs := make([][]int, len(jumps))
+		copy(dirs, jumps)
+		rng.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+
+		for _, d := range dirs {
+			nx, ny := cx+d[0], cy+d[1]
+			mx, my := cx+d[2], cy+d[3] // Wall between
+
+			// Check bounds for target
+			if nx >= 0 && nx < cols && ny >= 0 && ny < rows {
+				if grid[ny][nx].Type == models.Wall {
+					// Carve through wall
+					grid[my][mx].Type = models.Path
+					record(PhaseCarve, models.Path, models.Position{X: mx, Y: my}, models.Position{X: nx, Y: ny})
+					carve(nx, ny)
+				}
+			}
+		}
+	}
+
+	record(PhaseCarve, models.Path, models.Position{X: 0, Y: 0})
+	carve(0, 0)
+}
+
+// carvePrim carves the grid with randomized Prim's Algorithm.
+// Grows from 0,0 by connecting random frontier cells, which gives lots of
+// short branches and dead ends.
+func carvePrim(grid [][]models.Cell, rng *rand.Rand, record stepRecorder) {
+	rows, cols := len(grid), len(grid[0])
+	inBounds := func(x, y int) bool { return x >= 0 && x < cols && y >= 0 && y < rows }
+
+	type point struct{ X, Y int }
+	var frontier []point
+	queued := make(map[point]bool)
+
+	addFrontier := func(cx, cy int) {
+		for _, d := range jumps {
+			p := point{cx + d[0], cy + d[1]}
+			if inBounds(p.X, p.Y) && grid[p.Y][p.X].Type == models.Wall && !queued[p] {
+				queued[p] = true
+				frontier = append(frontier, p)
+			}
+		}
+	}
+
+	grid[0][0].Type = models.Path
+	record(PhaseCarve, models.Path, models.Position{X: 0, Y: 0})
+	addFrontier(0, 0)
+
+	for len(frontier) > 0 {
+		// Pick a random frontier cell.
+		i := rng.Intn(len(frontier))
+		cur := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		// Connect it to a random already carved cell 2 steps away.
+		dirs := make([][]int, len(jumps))
+		copy(dirs, jumps)
+		rng.Shuffle(len(dirs), func(i, j int) { dirs[i], dirs[j] = dirs[j], dirs[i] })
+		mid := models.Position{X: -1, Y: -1}
+		for _, d := range dirs {
+			nx, ny := cur.X+d[0], cur.Y+d[1]
+			if inBounds(nx, ny) && grid[ny][nx].Type == models.Path {
+				mid = models.Position{X: cur.X + d[2], Y: cur.Y + d[3]}
+				grid[mid.Y][mid.X].Type = models.Path
+				break
+			}
+		}
+
+		grid[cur.Y][cur.X].Type = models.Path
+		if mid.X >= 0 {
+			record(PhaseCarve, models.Path, mid, models.Position{X: cur.X, Y: cur.Y})
+		} else {
+			record(PhaseCarve, models.Path, models.Position{X: cur.X, Y: cur.Y})
+		}
+		addFrontier(cur.X, cur.Y)
+	}
 }
 
 // Function to handle player movement.
+// Every attempt is appended to the game's History so it can be replayed later.
 func MovePlayer(game *models.GameState, direction string) (string, int, error) { // Changed return type to include QuestionID
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if err := checkPlayable(game); err != nil {
+		return "", -1, err
+	}
+	switch direction {
+	case "UP", "DOWN", "LEFT", "RIGHT":
+	default:
+		// Would otherwise count as a move that goes nowhere.
+		return "", -1, fmt.Errorf("invalid direction %q", direction)
+	}
+
+	before := game.Player.CurrentPos
+	result, qID, pos := movePlayer(game, direction)
+	switch {
+	case result == "Blocked" || result == "Invalid Move":
+		game.Player.Bumps++
+	case result == "QuestionFound" && pos == before:
+		// Stopped at a question wall; it only counts once it opens.
+	default:
+		game.Player.Moves++
+	}
+	scoreMove(game, result, pos)
+	game.History = append(game.History, models.MoveRecord{
+		Seq:       len(game.History) + 1,
+		Direction: direction,
+		Result:    result,
+		Position:  pos,
+		At:        time.Now(),
+	})
+
+	// End the game only once the winning move is in the history.
+	if result == "Win" {
+		endGame(game, "WON", "")
+	}
+
+	return result, qID, nil
+}
+
+// movePlayer applies a single move and returns the result, the question ID
+// (or -1) and the position the move ended on.
+func movePlayer(game *models.GameState, direction string) (string, int, models.Position) {
 	// 1. Calculate new coordinate based on direction.
 	newPos := game.Player.CurrentPos
 	switch direction {
@@ -226,18 +419,22 @@ func MovePlayer(game *models.GameState, direction string) (string, int, error) {
 	// 2. Check bounds.
 	// If new coordinate is < 0 or >= size, return error (Invalid Move).
 	if newPos.X < 0 || newPos.X >= game.Board.Cols || newPos.Y < 0 || newPos.Y >= game.Board.Rows {
-		return "Invalid Move", -1, nil
+		return "Invalid Move", -1, game.Player.CurrentPos
 	}
 
 	// 3. Check cell type.
 	cell := game.Board.Grid[newPos.Y][newPos.X]
 
-	if cell.Type == models.Wall {
-		return "Blocked", -1, nil
+	// A question wall stays shut until its question is answered.
+	if cell.Type == models.Wall && cell.IsQuestionWall {
+		return "QuestionFound", cell.QuestionID, game.Player.CurrentPos
+	}
+	// Cells beyond the maze block like walls.
+	if cell.Type == models.Wall || cell.Type == models.Beyond {
+		return "Blocked", -1, game.Player.CurrentPos
 	}
 	if cell.Type == models.End {
-		game.Status = "WON"
-		return "Win", -1, nil
+		return "Win", -1, newPos
 	}
 
 	// 4. If Path or Start:
@@ -247,65 +444,90 @@ func MovePlayer(game *models.GameState, direction string) (string, int, error) {
 
 	// Check for standard question on path
 	if cell.HasQuestion {
-		return "QuestionFound", cell.QuestionID, nil
+		return "QuestionFound", cell.QuestionID, newPos
 	}
 
-	return "Moved", -1, nil
+	return "Moved", -1, newPos
 }
 
 // Function to check answer.
-func CheckAnswer(game *models.GameState, questionID int, answer string) bool {
+// Changes the game state, so callers must hold stateMu (see AnswerQuestion).
+func CheckAnswer(game *models.GameState, questionID int, answer models.Answer) bool {
 	// TODO: 1. Find the question from the store.
 	if questionID < 0 {
 		return false
 	}
-	// Simple lookup. Ideally use a map.
-	var question *models.Question
-	for _, q := range store.QuestionBank {
-		if q.ID == questionID {
-			question = &q
-			break
-		}
-	}
-
-	if question == nil {
+	question, ok := GetQuestion(questionID)
+	if !ok {
 		return false
 	}
 
 	// TODO: 2. Compare answer & 3. Logic.
-	if question.CorrectAns == answer {
-		game.Player.Score += 10
+	if answerMatches(question, answer) {
+		scoreAnswer(game, question)
 		return true
 	}
 
 	game.Player.Lives--
 	if game.Player.Lives == 0 {
-		game.Status = "LOST"
+		endGame(game, "LOST", ReasonNoLives)
 	}
 	return false
 }
 
+// questionAt finds a question on the player's cell or on a question wall
+// next to the player. Caller holds stateMu.
+func questionAt(game *models.GameState, questionID int) (models.Position, bool) {
+	p := game.Player.CurrentPos
+	if cell := game.Board.Grid[p.Y][p.X]; cell.HasQuestion && cell.QuestionID == questionID {
+		return p, true
+	}
+	for _, next := range pathfind.New(game.Board).Neighbors(p) {
+		if cell := game.Board.Grid[next.Y][next.X]; cell.IsQuestionWall && cell.QuestionID == questionID {
+			return next, true
+		}
+	}
+	return models.Position{}, false
+}
+
 // AnswerQuestion handles the logic for answering a question and returns the updated game state.
-func AnswerQuestion(game *models.GameState, questionID int, answer string) (*AnswerResult, error) {
+func AnswerQuestion(game *models.GameState, questionID int, answer models.Answer) (*AnswerResult, error) {
 	// Validate QuestionID
 	if questionID < 0 {
 		return nil, fmt.Errorf("invalid question ID")
 	}
 
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if err := checkPlayable(game); err != nil {
+		return nil, err
+	}
+
+	// Only the question the player stands on or is stopped at counts,
+	// otherwise one question could be answered for points again and again.
+	pos, ok := questionAt(game, questionID)
+	if !ok {
+		return nil, fmt.Errorf("question %d is not at the player's position", questionID)
+	}
+
 	// Check the answer
 	correct := CheckAnswer(game, questionID, answer)
 
+	result := &AnswerResult{Correct: correct}
 	if correct {
-		// Remove question from current pos if it was a path question
-		p := game.Player.CurrentPos
-		if game.Board.Grid[p.Y][p.X].HasQuestion && game.Board.Grid[p.Y][p.X].QuestionID == questionID {
-			game.Board.Grid[p.Y][p.X].HasQuestion = false
-			game.Board.Grid[p.Y][p.X].QuestionID = -1 // Reset QuestionID
+		cell := &game.Board.Grid[pos.Y][pos.X]
+		if cell.IsQuestionWall {
+			// Open the gate
+			cell.Type = models.Path
+			cell.IsQuestionWall = false
+			result.Opened = &pos
 		}
+		// Remove question from current pos if it was a path question
+		cell.HasQuestion = false
+		cell.QuestionID = -1 // Reset QuestionID
 	}
 
-	return &AnswerResult{
-		Correct:   correct,
-		GameState: game,
-	}, nil
+	result.GameState = snapshot(game)
+	return result, nil
 }
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"fmt"
+	"maze-game/models"
+	"time"
+)
+
+// Pause caps for ranked games. Zero means unlimited.
+var (
+	maxPauses    = 3
+	maxPauseTime = time.Minute
+)
+
+// SetPauseLimits configures how often and for how long in total a ranked
+// game may be paused. Zero disables a cap.
+func SetPauseLimits(pauses int, total time.Duration) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	maxPauses = pauses
+	maxPauseTime = total
+}
+
+// PauseGame stops the clock. Moves are rejected until the game resumes.
+// In ranked games running out of pause time resumes the game by itself.
+func PauseGame(game *models.GameState) (*models.GameState, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if err := checkPlayable(game); err != nil {
+		return nil, err
+	}
+
+	budget := time.Duration(-1)
+	if game.Ranked {
+		if maxPauses > 0 && game.Pauses >= maxPauses {
+			return nil, fmt.Errorf("no pauses left (limit %d)", maxPauses)
+		}
+		if maxPauseTime > 0 {
+			budget = maxPauseTime - time.Duration(game.PausedMs)*time.Millisecond
+			if budget <= 0 {
+				return nil, fmt.Errorf("no pause time left")
+			}
+		}
+	}
+
+	now := time.Now()
+	game.Status = "PAUSED"
+	game.PausedAt = &now
+	game.Pauses++
+	refreshClock(game)
+
+	if budget >= 0 {
+		armTimer(game, budget, func() { resumeGame(game) })
+	} else {
+		stopTimer(game)
+	}
+
+	return snapshot(game), nil
+}
+
+// ResumeGame restarts the clock of a paused game.
+func ResumeGame(game *models.GameState) (*models.GameState, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	if game.Status != "PAUSED" {
+		return nil, fmt.Errorf("game is not paused")
+	}
+	resumeGame(game)
+	return snapshot(game), nil
+}
+
+// resumeGame adds the pause to the paused total and re-arms the time
+// limit. Caller holds stateMu.
+func resumeGame(game *models.GameState) {
+	if game.Status != "PAUSED" || game.PausedAt == nil {
+		return
+	}
+	game.PausedMs += time.Since(*game.PausedAt).Milliseconds()
+	game.PausedAt = nil
+	game.Status = "ACTIVE"
+	refreshClock(game)
+	armTimeout(game)
+}
+
+// checkPlayable returns an error unless the game accepts moves right now.
+// Caller holds stateMu.
+func checkPlayable(game *models.GameState) error {
+	checkTimeout(game)
+	switch game.Status {
+	case "ACTIVE":
+		return nil
+	case "PAUSED":
+		return fmt.Errorf("game is paused")
+	default:
+		return fmt.Errorf("game is over")
+	}
+}
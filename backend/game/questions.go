@@ -0,0 +1,217 @@
+package game
+
+import (
+	"math/rand"
+	"maze-game/models"
+	"maze-game/pathfind"
+	"maze-game/store"
+)
+
+// Question density limits: the share of path cells that get a question.
+const (
+	DefaultQuestionDensity = 0.05 // Used by ModeQuiz when none is given
+	MaxQuestionDensity     = 0.5
+)
+
+// Question wall limits: gates on the route that open on a correct answer.
+const (
+	DefaultQuestionWalls = 2 // Used by ModeQuiz when none is given
+	MaxQuestionWalls     = 10
+)
+
+// Difficulties from the start of the route to the exit.
+var difficulties = []string{"easy", "medium", "hard"}
+
+// GetQuestion looks a question up in the bank.
+func GetQuestion(id int) (*models.Question, bool) {
+	q, ok := store.Questions().Get(id)
+	if !ok {
+		return nil, false
+	}
+	return &q, true
+}
+
+// placeQuestions puts question walls on the route to the exit and
+// questions on a share of the board's path cells. Cells early on the route
+// get easy questions and cells near the exit hard ones; side branches count
+// from where they leave the route. A question is used at most once, so
+// fewer cells get one if the bank runs out.
+func placeQuestions(board *models.Board, density float64, walls int, rng *rand.Rand) {
+	if density <= 0 && walls <= 0 {
+		return
+	}
+
+	progress := routeProgress(*board)
+
+	// Unused questions by difficulty. Unknown difficulties count as medium.
+	pools := make(map[string][]int)
+	for _, q := range store.Questions().All() {
+		d := q.Difficulty
+		if indexOf(difficulties, d) < 0 {
+			d = "medium"
+		}
+		pools[d] = append(pools[d], q.ID)
+	}
+	take := func(p models.Position) (int, bool) {
+		want := min(int(progress[p]*float64(len(difficulties))), len(difficulties)-1)
+		return takeQuestion(pools, want, rng)
+	}
+
+	// Gates first, so they get a question even when the bank is small.
+	for _, p := range gateCells(*board, walls) {
+		id, ok := take(p)
+		if !ok {
+			return
+		}
+		cell := &board.Grid[p.Y][p.X]
+		cell.Type = models.Wall
+		cell.IsQuestionWall = true
+		cell.QuestionID = id
+	}
+
+	var cells []models.Position
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			if _, ok := progress[models.Position{X: x, Y: y}]; ok && board.Grid[y][x].Type == models.Path {
+				cells = append(cells, models.Position{X: x, Y: y})
+			}
+		}
+	}
+	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	cells = cells[:int(float64(len(cells))*max(density, 0)+0.5)]
+
+	for _, p := range cells {
+		id, ok := take(p)
+		if !ok {
+			return
+		}
+		board.Grid[p.Y][p.X].HasQuestion = true
+		board.Grid[p.Y][p.X].QuestionID = id
+	}
+}
+
+// gateCells picks up to n cells for question walls, spread evenly over the
+// cells of the route that every way from the start to the exit goes
+// through, so a gate can't be walked around.
+func gateCells(board models.Board, n int) []models.Position {
+	if n <= 0 {
+		return nil
+	}
+	route := SolveMaze(board)
+	if len(route) < 3 {
+		return nil
+	}
+
+	// A route cell can be walked around if some detour leaves the route
+	// before it and comes back after it. Detours either run through cells
+	// off the route or jump straight between two route cells.
+	index := make(map[models.Position]int, len(route))
+	for i, p := range route {
+		index[p] = i
+	}
+	g := pathfind.New(board)
+	covered := make([]int, len(route)+1) // Difference array over route indexes
+	cover := func(lo, hi int) {
+		if hi-lo > 1 {
+			covered[lo+1]++
+			covered[hi]--
+		}
+	}
+	seen := make(map[models.Position]bool)
+	for i, p := range route {
+		for _, next := range g.Neighbors(p) {
+			if j, onRoute := index[next]; onRoute {
+				if j > i {
+					cover(i, j)
+				}
+				continue
+			}
+			if seen[next] {
+				continue
+			}
+			// Flood the off-route area and note where it touches the route.
+			lo, hi := len(route), -1
+			area := []models.Position{next}
+			seen[next] = true
+			for k := 0; k < len(area); k++ {
+				for _, q := range g.Neighbors(area[k]) {
+					if j, onRoute := index[q]; onRoute {
+						lo, hi = min(lo, j), max(hi, j)
+					} else if !seen[q] {
+						seen[q] = true
+						area = append(area, q)
+					}
+				}
+			}
+			cover(lo, hi)
+		}
+	}
+
+	// Cells that can't be walked around, leaving out the start and exit.
+	var cuts []models.Position
+	depth := 0
+	for i := range route {
+		depth += covered[i]
+		if depth == 0 && i > 0 && i < len(route)-1 {
+			cuts = append(cuts, route[i])
+		}
+	}
+
+	n = min(n, len(cuts))
+	gates := make([]models.Position, n)
+	for i := range gates {
+		gates[i] = cuts[(2*i+1)*len(cuts)/(2*n)]
+	}
+	return gates
+}
+
+// takeQuestion removes a random question of the wanted difficulty from the
+// pools, or of the closest difficulty that has one left.
+func takeQuestion(pools map[string][]int, want int, rng *rand.Rand) (int, bool) {
+	for dist := 0; dist < len(difficulties); dist++ {
+		for _, i := range []int{want - dist, want + dist} {
+			if i < 0 || i >= len(difficulties) {
+				continue
+			}
+			pool := pools[difficulties[i]]
+			if len(pool) == 0 {
+				continue
+			}
+			n := rng.Intn(len(pool))
+			id := pool[n]
+			pool[n] = pool[len(pool)-1]
+			pools[difficulties[i]] = pool[:len(pool)-1]
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// routeProgress maps every reachable cell to how far along the route from
+// start to exit it lies, from 0 at the start to 1 at the exit. Cells off
+// the route take the value of the route cell their branch leaves from.
+func routeProgress(board models.Board) map[models.Position]float64 {
+	route := SolveMaze(board)
+	if len(route) < 2 {
+		return nil
+	}
+
+	g := pathfind.New(board)
+	progress := make(map[models.Position]float64)
+	queue := make([]models.Position, 0, len(route))
+	for i, p := range route {
+		progress[p] = float64(i) / float64(len(route)-1)
+		queue = append(queue, p)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range g.Neighbors(cur) {
+			if _, seen := progress[next]; !seen {
+				progress[next] = progress[cur]
+				queue = append(queue, next)
+			}
+		}
+	}
+	return progress
+}
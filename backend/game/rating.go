@@ -0,0 +1,37 @@
+package game
+
+import "maze-game/models"
+
+// Star thresholds as the ratio of moves to par. Anything slower than
+// twoStarRatio still gets one star for finishing.
+const (
+	threeStarRatio = 1.25
+	twoStarRatio   = 2.0
+)
+
+// par is the number of moves on the shortest route from the start to an
+// exit, or 0 when no exit can be reached.
+func par(board models.Board) int {
+	path := SolveMaze(board)
+	if len(path) == 0 {
+		return 0
+	}
+	return len(path) - 1
+}
+
+// starRating grades a win from 1 to 3 stars by how close the move count
+// came to par.
+func starRating(moves, par int) int {
+	if par <= 0 {
+		return 3
+	}
+	ratio := float64(moves) / float64(par)
+	switch {
+	case ratio <= threeStarRatio:
+		return 3
+	case ratio <= twoStarRatio:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,65 @@
+package game
+
+import "maze-game/models"
+
+// Replay is everything a client needs to play a game back from the start.
+type Replay struct {
+	GameID string              `json:"game_id"`
+	Status string              `json:"status"`
+	Board  models.Board        `json:"board"`
+	Start  models.Position     `json:"start"`
+	Moves  []models.MoveRecord `json:"moves"`
+}
+
+// BuildReplay collects the starting board and full move history of a game.
+// It copies both, so the replay can be read while the game goes on.
+func BuildReplay(game *models.GameState) *Replay {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	moves := make([]models.MoveRecord, len(game.History))
+	copy(moves, game.History)
+
+	return &Replay{
+		GameID: game.ID,
+		Status: game.Status,
+		Board:  copyBoard(game.StartBoard),
+		Start:  findStart(game.StartBoard),
+		Moves:  moves,
+	}
+}
+
+// PositionAt returns where the player stood after the first n moves.
+// n = 0 is the starting position.
+func (r *Replay) PositionAt(n int) models.Position {
+	if n <= 0 || len(r.Moves) == 0 {
+		return r.Start
+	}
+	if n > len(r.Moves) {
+		n = len(r.Moves)
+	}
+	return r.Moves[n-1].Position
+}
+
+// findStart locates the START cell, falling back to (0,0).
+func findStart(board models.Board) models.Position {
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			if board.Grid[y][x].Type == models.Start {
+				return models.Position{X: x, Y: y}
+			}
+		}
+	}
+	return models.Position{X: 0, Y: 0}
+}
+
+// Path returns the sequence of positions the player occupied, starting
+// with the start cell.
+func (r *Replay) Path() []models.Position {
+	path := make([]models.Position, 0, len(r.Moves)+1)
+	path = append(path, r.Start)
+	for _, m := range r.Moves {
+		path = append(path, m.Position)
+	}
+	return path
+}
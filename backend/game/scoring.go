@@ -0,0 +1,160 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"maze-game/models"
+	"os"
+	"time"
+)
+
+// Names of the scoring rules as they appear in a score breakdown.
+const (
+	RuleExplore   = "explore"
+	RuleBump      = "bump"
+	RuleQuestion  = "question" // Suffixed with the difficulty, e.g. "question_hard"
+	RuleTimeBonus = "time_bonus"
+	RuleParBonus  = "par_bonus"
+)
+
+// Points for a correct answer whose difficulty has no entry in the rules.
+const defaultQuestionPoints = 10
+
+// ScoringRules decides how many points each action in a game is worth.
+type ScoringRules struct {
+	ExplorePoints  int            `json:"explore_points"`  // Per cell visited for the first time
+	BumpPenalty    int            `json:"bump_penalty"`    // Taken off per move into a wall or the edge
+	QuestionPoints map[string]int `json:"question_points"` // Per correct answer, by difficulty
+	// Full time bonus for an instant win, shrinking to nothing after
+	// TimeBonusSeconds. Timed games use their time limit instead.
+	TimeBonus        int `json:"time_bonus"`
+	TimeBonusSeconds int `json:"time_bonus_seconds"`
+	// Full par bonus for a win in par moves, shrinking as moves go over par.
+	ParBonus int `json:"par_bonus"`
+	// Price of a hint in points and in lives.
+	HintCost     int `json:"hint_cost"`
+	HintLifeCost int `json:"hint_life_cost"`
+}
+
+// ScoringConfig is the scoring config file: default rules plus overrides
+// per game mode. A mode only needs to list the rules it changes.
+type ScoringConfig struct {
+	Default ScoringRules               `json:"default"`
+	Modes   map[string]json.RawMessage `json:"modes"`
+}
+
+var (
+	defaultRules = ScoringRules{
+		ExplorePoints:    1,
+		BumpPenalty:      1,
+		QuestionPoints:   map[string]int{"easy": 5, "medium": 10, "hard": 20},
+		TimeBonus:        50,
+		TimeBonusSeconds: 300,
+		ParBonus:         50,
+		HintCost:         10,
+	}
+	// Rules per mode, falling back to defaultRules. Set once at startup.
+	modeRules = map[string]ScoringRules{}
+)
+
+// LoadScoringRules reads the scoring config file. A missing file keeps the
+// built-in rules.
+func LoadScoringRules(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	config := ScoringConfig{Default: defaultRules}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	rules := make(map[string]ScoringRules, len(config.Modes))
+	for mode, raw := range config.Modes {
+		if !ValidMode(mode) {
+			return fmt.Errorf("%s: unknown mode %q", path, mode)
+		}
+		r := config.Default
+		r.QuestionPoints = maps.Clone(config.Default.QuestionPoints)
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return fmt.Errorf("%s: mode %q: %w", path, mode, err)
+		}
+		rules[mode] = r
+	}
+
+	defaultRules = config.Default
+	modeRules = rules
+	return nil
+}
+
+// RulesFor returns the scoring rules of a game mode.
+func RulesFor(mode string) ScoringRules {
+	if r, ok := modeRules[mode]; ok {
+		return r
+	}
+	return defaultRules
+}
+
+// award adds points to the player's score and to the game's breakdown.
+// Rules switched off (0 points) stay out of the breakdown. The caller must
+// hold stateMu.
+func award(game *models.GameState, rule string, points int) {
+	if points == 0 {
+		return
+	}
+	game.Player.Score += points
+	for i := range game.ScoreBreakdown {
+		if game.ScoreBreakdown[i].Rule == rule {
+			game.ScoreBreakdown[i].Count++
+			game.ScoreBreakdown[i].Points += points
+			return
+		}
+	}
+	game.ScoreBreakdown = append(game.ScoreBreakdown, models.ScoreItem{Rule: rule, Count: 1, Points: points})
+}
+
+// scoreMove awards the points for a move attempt.
+func scoreMove(game *models.GameState, result string, pos models.Position) {
+	rules := RulesFor(game.Mode)
+	switch result {
+	case "Moved", "QuestionFound", "Win":
+		if !game.Visited[pos] {
+			game.Visited[pos] = true
+			award(game, RuleExplore, rules.ExplorePoints)
+		}
+	case "Blocked", "Invalid Move":
+		award(game, RuleBump, -rules.BumpPenalty)
+	}
+}
+
+// scoreAnswer awards the points for a correct answer.
+func scoreAnswer(game *models.GameState, question *models.Question) {
+	points, ok := RulesFor(game.Mode).QuestionPoints[question.Difficulty]
+	if !ok {
+		points = defaultQuestionPoints
+	}
+	award(game, RuleQuestion+"_"+question.Difficulty, points)
+}
+
+// scoreWin awards the time and par bonuses once a game is won.
+func scoreWin(game *models.GameState) {
+	rules := RulesFor(game.Mode)
+
+	window := time.Duration(rules.TimeBonusSeconds) * time.Second
+	if game.TimeLimitMs > 0 {
+		window = time.Duration(game.TimeLimitMs) * time.Millisecond
+	}
+	if window > 0 {
+		left := max(window-Elapsed(game), 0)
+		award(game, RuleTimeBonus, int(int64(rules.TimeBonus)*int64(left)/int64(window)))
+	}
+
+	if game.Par > 0 && game.Player.Moves > 0 {
+		award(game, RuleParBonus, rules.ParBonus*game.Par/max(game.Player.Moves, game.Par))
+	}
+}
@@ -0,0 +1,12 @@
+package game
+
+import (
+	"maze-game/models"
+	"maze-game/pathfind"
+)
+
+// SolveMaze returns the shortest route from the START cell to the EXIT
+// (both included), or nil if the exit cannot be reached.
+func SolveMaze(board models.Board) []models.Position {
+	return pathfind.BFS(pathfind.New(board), findStart(board), pathfind.IsType(board, models.End))
+}
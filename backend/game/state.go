@@ -1,7 +1,12 @@
 package game
 
 import (
+	"math"
+	"math/rand"
 	"maze-game/models"
+	"maze-game/store"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,16 +14,68 @@ import (
 // In-Memory Store for active games.
 // Since we aren't using a database, we keep games in a global map.
 
-var activeGames = make(map[string]*models.GameState)
+var (
+	gamesMu     sync.RWMutex
+	activeGames = make(map[string]*models.GameState)
+)
+
+// stateMu serializes changes to games: moves and answers arrive over HTTP
+// and WebSocket, and the clock can end a game on its own.
+var stateMu sync.Mutex
 
 // NewGame creates a new game session.
 func NewGame(rows, cols int) *models.GameState {
+	return NewGameWithOptions(GameOptions{
+		Maze: MazeOptions{Rows: rows, Cols: cols, Braiding: DefaultBraiding},
+		Mode: ModeClassic,
+	})
+}
+
+// NewGameWithOptions creates a new game session on a generated maze and
+// attaches the share code that rebuilds it.
+func NewGameWithOptions(opts GameOptions) *models.GameState {
+	if opts.Maze.Seed == 0 {
+		opts.Maze.Seed = RandomSeed()
+	}
+	if opts.Maze.Algorithm == "" {
+		opts.Maze.Algorithm = AlgorithmBacktracking
+	}
+	if opts.Mode == "" {
+		opts.Mode = ModeClassic
+	}
+	if opts.Mode == ModeQuiz && opts.QuestionDensity == 0 && opts.QuestionWalls == 0 {
+		opts.QuestionDensity = DefaultQuestionDensity
+		opts.QuestionWalls = DefaultQuestionWalls
+	}
+	// Codes store braiding and density in whole percent, round here so a
+	// game started from its own code gets exactly the same maze.
+	opts.Maze.Braiding = math.Round(opts.Maze.Braiding*100) / 100
+	opts.QuestionDensity = math.Round(opts.QuestionDensity*100) / 100
+
 	// Generate a new board
-	board := GenerateMaze(rows, cols)
+	board := GenerateMazeWithOptions(opts.Maze)
+	placeQuestions(&board, opts.QuestionDensity, opts.QuestionWalls, rand.New(rand.NewSource(opts.Maze.Seed)))
+	gameState := NewGameFromBoard(board)
+	gameState.Mode = opts.Mode
+	gameState.Code = EncodeCode(opts)
+	gameState.Ranked = true
+
+	if opts.Mode == ModeTimeAttack {
+		SetTimeLimit(gameState, timeAttackLimit(gameState.Board))
+	}
+
+	return gameState
+}
 
-	// Create player at (0,0)
+// NewGameFromBoard creates a new game session on an existing board,
+// e.g. one imported from ASCII. Such boards have no share code to rank
+// them by, so the game is unranked.
+func NewGameFromBoard(board models.Board) *models.GameState {
+	assignQuestions(&board)
+
+	// Create player on the start cell
 	player := models.Player{
-		CurrentPos: models.Position{X: 0, Y: 0},
+		CurrentPos: findStart(board),
 		Lives:      3,
 		Score:      0,
 	}
@@ -26,21 +83,101 @@ func NewGame(rows, cols int) *models.GameState {
 	// Create GameState
 	id := uuid.New().String()
 	gameState := &models.GameState{
-		ID:     id,
-		Board:  board,
-		Player: player,
-		Status: "ACTIVE",
+		ID:         id,
+		Board:      board,
+		StartBoard: copyBoard(board),
+		Player:     player,
+		Status:     "ACTIVE",
+		Mode:       ModeClassic,
+		StartedAt:  time.Now(),
+		Par:        par(board),
+		Visited:    map[models.Position]bool{player.CurrentPos: true},
 	}
 
 	// Store in map
+	gamesMu.Lock()
 	activeGames[id] = gameState
+	gamesMu.Unlock()
 
 	return gameState
 }
 
-// Retrieve a game by ID.
+// endGame moves a game to its final status and records the result.
+// Caller holds stateMu.
+func endGame(game *models.GameState, status, reason string) {
+	now := time.Now()
+	game.Status = status
+	game.Reason = reason
+	game.FinishedAt = &now
+	stopTimer(game)
+	refreshClock(game)
+
+	if status == "WON" {
+		game.Stars = starRating(game.Player.Moves, game.Par)
+		scoreWin(game)
+		if game.Ranked {
+			game.Rank = recordResult(game)
+		}
+	}
+}
+
+// Retrieve a game by ID, with its clock brought up to date.
 func GetGame(id string) (*models.GameState, bool) {
-	// TODO: Check if id exists in activeGames.
+	gamesMu.RLock()
 	game, exists := activeGames[id]
+	gamesMu.RUnlock()
+
+	if exists {
+		RefreshClock(game)
+	}
 	return game, exists
 }
+
+// assignQuestions gives every question cell and question wall without a
+// known question a random one from the bank, preferring questions not yet
+// on the board. With an empty bank question walls become plain paths.
+func assignQuestions(board *models.Board) {
+	questions := store.Questions().All()
+	used := make(map[int]bool)
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			if cell := board.Grid[y][x]; cell.HasQuestion || cell.IsQuestionWall {
+				used[cell.QuestionID] = true
+			}
+		}
+	}
+
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			cell := &board.Grid[y][x]
+			if (!cell.HasQuestion && !cell.IsQuestionWall) || questionExists(cell.QuestionID) {
+				continue
+			}
+			if len(questions) == 0 {
+				if cell.IsQuestionWall {
+					cell.Type = models.Path
+					cell.IsQuestionWall = false
+				}
+				cell.HasQuestion = false
+				continue
+			}
+			var unused []int
+			for _, q := range questions {
+				if !used[q.ID] {
+					unused = append(unused, q.ID)
+				}
+			}
+			if len(unused) > 0 {
+				cell.QuestionID = unused[rand.Intn(len(unused))]
+			} else {
+				cell.QuestionID = questions[rand.Intn(len(questions))].ID
+			}
+			used[cell.QuestionID] = true
+		}
+	}
+}
+
+func questionExists(id int) bool {
+	_, ok := GetQuestion(id)
+	return ok
+}
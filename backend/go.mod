@@ -7,3 +7,5 @@ require github.com/joho/godotenv v1.5.1
 require github.com/google/uuid v1.6.0
 
 require github.com/gorilla/websocket v1.5.3
+
+require gopkg.in/yaml.v3 v3.0.1
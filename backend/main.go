@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"log"
 	"maze-game/api"
+	"maze-game/game"
 	"maze-game/store"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -27,9 +30,39 @@ func main() {
 	// TODO: 2. Load your data.
 	// We need to load the questions from the JSON file at the start.
 	// Call store.LoadQuestions() here.
-	if err := store.LoadQuestions("store/questions.json"); err != nil {
+	// QUESTIONS_PATH can point to another file or a directory of them.
+	questionsPath := os.Getenv("QUESTIONS_PATH")
+	if questionsPath == "" {
+		questionsPath = "store/questions.json"
+	}
+	if err := store.LoadQuestions(questionsPath); err != nil {
 		log.Fatalf("Failed to load questions: %v", err)
 	}
+	// Pick up edits without a restart, e.g. QUESTIONS_RELOAD_SECONDS=2.
+	// 0 turns it off.
+	reloadSeconds := 2
+	if v, err := strconv.Atoi(os.Getenv("QUESTIONS_RELOAD_SECONDS")); err == nil {
+		reloadSeconds = v
+	}
+	if reloadSeconds > 0 {
+		store.WatchQuestions(questionsPath, time.Duration(reloadSeconds)*time.Second)
+	}
+
+	// Points per action, with overrides per game mode.
+	if err := game.LoadScoringRules("store/scoring.json"); err != nil {
+		log.Fatalf("Failed to load scoring rules: %v", err)
+	}
+
+	// Pause caps for ranked games, e.g. MAX_PAUSES=3 MAX_PAUSE_SECONDS=60.
+	// Unset keeps the defaults, 0 removes the cap.
+	maxPauses, maxPauseSeconds := 3, 60
+	if v, err := strconv.Atoi(os.Getenv("MAX_PAUSES")); err == nil {
+		maxPauses = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("MAX_PAUSE_SECONDS")); err == nil {
+		maxPauseSeconds = v
+	}
+	game.SetPauseLimits(maxPauses, time.Duration(maxPauseSeconds)*time.Second)
 
 	// TODO: 3. Set up your HTTP server and routes.
 	// We will use the 'api' package to define our routes.
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
 // Define your data structures here. Start simple.
 
 // Position represents x,y coordinates on the grid.
@@ -35,13 +42,19 @@ type Board struct {
 	Rows int      `json:"rows"`
 	Cols int      `json:"cols"`
 	Grid [][]Cell `json:"grid"`
+	// Seed the board was generated from (0 for hand-made boards).
+	Seed int64 `json:"seed,omitempty"`
 }
 
 // Player represents the user's state.
 type Player struct {
+	Name       string   `json:"name,omitempty"`
 	CurrentPos Position `json:"current_pos"`
 	Lives      int      `json:"lives"`
 	Score      int      `json:"score"`
+	HintsUsed  int      `json:"hints_used"`
+	Moves      int      `json:"moves"` // Steps that moved the player
+	Bumps      int      `json:"bumps"` // Moves blocked by a wall or the edge
 }
 
 // GameState represents the entire state of a single game session.
@@ -49,14 +62,141 @@ type GameState struct {
 	ID     string `json:"id"`
 	Board  Board  `json:"board"`
 	Player Player `json:"player"`
-	Status string `json:"status"` // "ACTIVE", "WON", "LOST"
+	Status string `json:"status"`           // "ACTIVE", "PAUSED", "WON", "LOST"
+	Reason string `json:"reason,omitempty"` // Why a game was lost, e.g. "TIMEOUT"
+	Mode   string `json:"mode"`
+	// Share code to rebuild the same maze. Empty for uploaded boards.
+	Code string `json:"code,omitempty"`
+	// Only ranked games are scored, e.g. a repeated daily challenge or an
+	// uploaded board is not.
+	Ranked     bool       `json:"ranked"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Clock, refreshed by the server whenever the game is read or changed.
+	ElapsedMs       int64  `json:"elapsed_ms"`
+	TimeLimitMs     int64  `json:"time_limit_ms,omitempty"`
+	TimeRemainingMs *int64 `json:"time_remaining_ms,omitempty"`
+	// Pauses taken so far, total paused time and when the current pause began.
+	Pauses   int        `json:"pauses"`
+	PausedMs int64      `json:"paused_ms"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	// Leaderboard position among games on the same maze, set on a ranked win.
+	Rank int `json:"rank,omitempty"`
+	// Fewest moves needed to reach the exit, and the 1-3 star rating
+	// earned against it on a win.
+	Par   int `json:"par"`
+	Stars int `json:"stars,omitempty"`
+	// Where the score came from, one entry per scoring rule.
+	ScoreBreakdown []ScoreItem `json:"score_breakdown,omitempty"`
+	// Cells the player has stepped on, for exploration points.
+	Visited map[Position]bool `json:"-"`
+	// Every move attempt in order. Kept out of the regular JSON so move
+	// responses stay small; the replay endpoint serves it instead.
+	History []MoveRecord `json:"-"`
+	// The board as the game began, before any gate was opened or question
+	// answered. Replays start from it.
+	StartBoard Board `json:"-"`
+}
+
+// ScoreItem is the total awarded by one scoring rule in a game.
+type ScoreItem struct {
+	Rule   string `json:"rule"`
+	Count  int    `json:"count"`
+	Points int    `json:"points"`
+}
+
+// MoveRecord is a single entry in a game's move history.
+type MoveRecord struct {
+	Seq       int       `json:"seq"`
+	Direction string    `json:"direction"`
+	Result    string    `json:"result"`
+	Position  Position  `json:"position"` // Player position after the move.
+	At        time.Time `json:"at"`
 }
 
-// Question represents a quiz question.
+// QuestionType says how a question is answered.
+type QuestionType string
+
+const (
+	Choice      QuestionType = "choice"       // Pick one of the options
+	TrueFalse   QuestionType = "true_false"   // "true" or "false"
+	FreeText    QuestionType = "text"         // Typed answer, case and punctuation don't matter
+	Numeric     QuestionType = "numeric"      // A number, within Tolerance
+	MultiSelect QuestionType = "multi_select" // Pick exactly the CorrectAnswers options
+)
+
+// Question represents a quiz question. Question banks can also be
+// written in YAML, with the same field names.
 type Question struct {
-	ID         int      `json:"id"`
-	Text       string   `json:"text"`
-	Options    []string `json:"options"`
-	CorrectAns string   `json:"correct_ans"` // Should probably not send this to frontend immediately!
-	Difficulty string   `json:"difficulty"`
+	ID         int          `json:"id" yaml:"id"`
+	Type       QuestionType `json:"type,omitempty" yaml:"type,omitempty"` // Choice when empty
+	Text       string       `json:"text" yaml:"text"`
+	Options    []string     `json:"options,omitempty" yaml:"options,omitempty"`
+	CorrectAns string       `json:"correct_ans" yaml:"correct_ans,omitempty"` // Never sent to players, see PublicQuestion
+	Difficulty string       `json:"difficulty" yaml:"difficulty,omitempty"`
+	Category   string       `json:"category,omitempty" yaml:"category,omitempty"`
+	// Other accepted answers to a text question.
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Whether a text question forgives small typos.
+	Fuzzy bool `json:"fuzzy,omitempty" yaml:"fuzzy,omitempty"`
+	// How far off an answer to a numeric question may be.
+	Tolerance float64 `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	// The options a multi_select question needs, in place of CorrectAns.
+	CorrectAnswers []string `json:"correct_answers,omitempty" yaml:"correct_answers,omitempty"`
+}
+
+// Kind returns the question's type, Choice when none is set.
+func (q Question) Kind() QuestionType {
+	if q.Type == "" {
+		return Choice
+	}
+	return q.Type
+}
+
+// PublicQuestion is a question as players see it, without the answer.
+type PublicQuestion struct {
+	ID         int          `json:"id"`
+	Type       QuestionType `json:"type"`
+	Text       string       `json:"text"`
+	Options    []string     `json:"options,omitempty"`
+	Difficulty string       `json:"difficulty"`
+	Category   string       `json:"category,omitempty"`
+}
+
+// Public drops the answer from a question.
+func (q Question) Public() *PublicQuestion {
+	options := q.Options
+	if q.Kind() == TrueFalse {
+		options = []string{"true", "false"}
+	}
+	return &PublicQuestion{ID: q.ID, Type: q.Kind(), Text: q.Text, Options: options, Difficulty: q.Difficulty, Category: q.Category}
+}
+
+// Answer is what a player answers: one value, or several for a
+// multi_select question. In JSON it is a string, number or boolean, or an
+// array of strings.
+type Answer []string
+
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	var many []string
+	if err := json.Unmarshal(data, &many); err == nil {
+		*a = many
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = Answer{one}
+		return nil
+	}
+	// Numbers and booleans are kept as written.
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value.(type) {
+	case float64, bool:
+		*a = Answer{string(bytes.TrimSpace(data))}
+		return nil
+	}
+	return fmt.Errorf("answer must be a string, number, boolean or list of strings")
 }
@@ -0,0 +1,207 @@
+package pathfind
+
+import (
+	"container/heap"
+	"fmt"
+	"maze-game/models"
+)
+
+// Solvers that Explore can animate.
+const (
+	SolverBFS          = "bfs"
+	SolverDFS          = "dfs"
+	SolverAStar        = "astar"
+	SolverWallFollower = "wall_follower"
+)
+
+// Step is one step of a solver: the cell it just expanded and the cells it
+// added to its frontier while doing so.
+type Step struct {
+	Visited  models.Position   `json:"visited"`
+	Frontier []models.Position `json:"frontier,omitempty"`
+}
+
+// ValidSolver reports whether Explore knows a solver.
+func ValidSolver(name string) bool {
+	switch name {
+	case SolverBFS, SolverDFS, SolverAStar, SolverWallFollower:
+		return true
+	}
+	return false
+}
+
+// Explore runs a solver from a cell toward the goal and reports every step
+// to onStep as it happens. It returns the route found, or nil if there is
+// none or onStep stopped the search by returning false. The heuristic is
+// only used by A*.
+func Explore(g *Graph, solver string, from models.Position, goal func(models.Position) bool, h Heuristic, onStep func(Step) bool) ([]models.Position, error) {
+	switch solver {
+	case SolverBFS:
+		return exploreBFS(g, from, goal, onStep), nil
+	case SolverDFS:
+		return exploreDFS(g, from, goal, onStep), nil
+	case SolverAStar:
+		return exploreAStar(g, from, goal, h, onStep), nil
+	case SolverWallFollower:
+		return exploreWallFollower(g, from, goal, onStep), nil
+	}
+	return nil, fmt.Errorf("unknown solver %q", solver)
+}
+
+func exploreBFS(g *Graph, from models.Position, goal func(models.Position) bool, onStep func(Step) bool) []models.Position {
+	prev := make(map[models.Position]models.Position)
+	visited := map[models.Position]bool{from: true}
+	queue := []models.Position{from}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		step := Step{Visited: cur}
+		if !goal(cur) {
+			for _, next := range g.Neighbors(cur) {
+				if !visited[next] {
+					visited[next] = true
+					prev[next] = cur
+					queue = append(queue, next)
+					step.Frontier = append(step.Frontier, next)
+				}
+			}
+		}
+		if !onStep(step) {
+			return nil
+		}
+		if goal(cur) {
+			return walkBack(prev, from, cur)
+		}
+	}
+	return nil
+}
+
+func exploreDFS(g *Graph, from models.Position, goal func(models.Position) bool, onStep func(Step) bool) []models.Position {
+	prev := make(map[models.Position]models.Position)
+	visited := make(map[models.Position]bool)
+	stack := []models.Position{from}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+
+		step := Step{Visited: cur}
+		if !goal(cur) {
+			// Push in reverse so the first direction is explored first.
+			neighbors := g.Neighbors(cur)
+			for i := len(neighbors) - 1; i >= 0; i-- {
+				if next := neighbors[i]; !visited[next] {
+					prev[next] = cur
+					stack = append(stack, next)
+					step.Frontier = append(step.Frontier, next)
+				}
+			}
+		}
+		if !onStep(step) {
+			return nil
+		}
+		if goal(cur) {
+			return walkBack(prev, from, cur)
+		}
+	}
+	return nil
+}
+
+func exploreAStar(g *Graph, from models.Position, goal func(models.Position) bool, h Heuristic, onStep func(Step) bool) []models.Position {
+	if h == nil {
+		h = Zero
+	}
+	cost := map[models.Position]int{from: 0}
+	prev := make(map[models.Position]models.Position)
+	done := make(map[models.Position]bool)
+	open := &nodeQueue{{pos: from, priority: h(from)}}
+
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(node).pos
+		if done[cur] {
+			continue
+		}
+		done[cur] = true
+
+		step := Step{Visited: cur}
+		if !goal(cur) {
+			for _, next := range g.Neighbors(cur) {
+				c := cost[cur] + g.cost(next)
+				if known, seen := cost[next]; seen && known <= c {
+					continue
+				}
+				cost[next] = c
+				prev[next] = cur
+				heap.Push(open, node{pos: next, priority: c + h(next)})
+				step.Frontier = append(step.Frontier, next)
+			}
+		}
+		if !onStep(step) {
+			return nil
+		}
+		if goal(cur) {
+			return walkBack(prev, from, cur)
+		}
+	}
+	return nil
+}
+
+// exploreWallFollower keeps its right hand on the wall. The route it
+// returns is its walk with the dead-end detours cut out. It gives up when
+// it comes back to a cell facing the same way, since it would only loop.
+func exploreWallFollower(g *Graph, from models.Position, goal func(models.Position) bool, onStep func(Step) bool) []models.Position {
+	// Clockwise, so turning right is +1.
+	clockwise := []models.Position{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}}
+	type state struct {
+		pos models.Position
+		dir int
+	}
+	seen := make(map[state]bool)
+	route := []models.Position{from}
+	index := map[models.Position]int{from: 0}
+	cur, dir := from, 0
+
+	for {
+		if !onStep(Step{Visited: cur}) {
+			return nil
+		}
+		if goal(cur) {
+			return route
+		}
+		if seen[state{cur, dir}] {
+			return nil
+		}
+		seen[state{cur, dir}] = true
+
+		// Try right, straight, left, then turn back.
+		moved := false
+		for _, turn := range []int{1, 0, 3, 2} {
+			d := (dir + turn) % 4
+			next := models.Position{X: cur.X + clockwise[d].X, Y: cur.Y + clockwise[d].Y}
+			if g.CanEnter(next) {
+				cur, dir, moved = next, d, true
+				break
+			}
+		}
+		if !moved {
+			return nil // Walled in on all sides.
+		}
+
+		if i, ok := index[cur]; ok {
+			// Back on the route: drop the detour.
+			for _, p := range route[i+1:] {
+				delete(index, p)
+			}
+			route = route[:i+1]
+		} else {
+			index[cur] = len(route)
+			route = append(route, cur)
+		}
+	}
+}
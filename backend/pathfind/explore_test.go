@@ -0,0 +1,70 @@
+package pathfind
+
+import (
+	"slices"
+	"testing"
+
+	"maze-game/models"
+)
+
+func TestWallFollowerDropsDetours(t *testing.T) {
+	// Keeping its right hand on the wall, the follower turns down into the
+	// dead end below the middle before it finds the exit.
+	tests := []struct {
+		name   string
+		board  models.Board
+		detour bool // Whether the walk strays from the route
+	}{
+		{"dead end on the way", board(
+			"S.#...",
+			"#.#.#.",
+			"#...#E",
+			"###.##",
+			"###..#"), true},
+		{"no detour", board("S...E"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.board)
+			start, exit := Find(tt.board, models.Start)[0], Find(tt.board, models.End)[0]
+
+			steps := 0
+			route, err := Explore(g, SolverWallFollower, start, IsAt(exit), Zero, func(Step) bool {
+				steps++
+				return true
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRoute(t, g, route, start, exit)
+
+			// These mazes have no loops, so without its detours the walk
+			// is the one simple route, which BFS finds too.
+			if want := BFS(g, start, IsAt(exit)); !slices.Equal(route, want) {
+				t.Errorf("got route %v, want %v", route, want)
+			}
+			if tt.detour && steps <= len(route) {
+				t.Errorf("took %d steps for a %d cell route, the detour was never walked", steps, len(route))
+			}
+		})
+	}
+}
+
+func TestWallFollowerGivesUp(t *testing.T) {
+	// The exit sits in the middle of an open room, away from every wall,
+	// so the follower circles the room forever and must stop.
+	b := board(
+		"S....",
+		".....",
+		"..E..",
+		".....",
+		".....")
+	g := New(b)
+	route, err := Explore(g, SolverWallFollower, pos(0, 0), IsAt(pos(2, 2)), Zero, func(Step) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route != nil {
+		t.Errorf("got route %v, want none", route)
+	}
+}
@@ -0,0 +1,129 @@
+// Package pathfind runs graph searches over a maze board. Every cell is a
+// node connected to its four neighbours; a Graph decides which cells can be
+// entered and what entering them costs.
+package pathfind
+
+import "maze-game/models"
+
+// Graph is a board seen as a grid graph.
+type Graph struct {
+	Board models.Board
+	// Passable reports whether a cell can be entered. Defaults to
+	// DefaultPassable.
+	Passable func(models.Cell) bool
+	// Cost is the price of entering a cell, at least 1. Defaults to 1 for
+	// every cell. Only Dijkstra and AStar look at it.
+	Cost func(models.Cell) int
+}
+
+// New returns a graph over a board with the default rules.
+func New(board models.Board) *Graph {
+	return &Graph{Board: board}
+}
+
+// DefaultPassable lets the player through everything but walls and cells
+// beyond the maze. Question walls count as passable since they open once
+// answered.
+func DefaultPassable(cell models.Cell) bool {
+	if cell.IsQuestionWall {
+		return true
+	}
+	return cell.Type != models.Wall && cell.Type != models.Beyond
+}
+
+// Directions in the order searches try them.
+var directions = []models.Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}}
+
+// InBounds reports whether a position lies on the board.
+func (g *Graph) InBounds(p models.Position) bool {
+	return p.X >= 0 && p.X < g.Board.Cols && p.Y >= 0 && p.Y < g.Board.Rows
+}
+
+// Cell returns the cell at a position on the board.
+func (g *Graph) Cell(p models.Position) models.Cell {
+	return g.Board.Grid[p.Y][p.X]
+}
+
+// CanEnter reports whether a position is on the board and passable.
+func (g *Graph) CanEnter(p models.Position) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	if g.Passable == nil {
+		return DefaultPassable(g.Cell(p))
+	}
+	return g.Passable(g.Cell(p))
+}
+
+// Neighbors returns the passable cells next to p.
+func (g *Graph) Neighbors(p models.Position) []models.Position {
+	var out []models.Position
+	for _, d := range directions {
+		next := models.Position{X: p.X + d.X, Y: p.Y + d.Y}
+		if g.CanEnter(next) {
+			out = append(out, next)
+		}
+	}
+	return out
+}
+
+func (g *Graph) cost(p models.Position) int {
+	if g.Cost == nil {
+		return 1
+	}
+	return max(g.Cost(g.Cell(p)), 1)
+}
+
+// Find returns every position holding a cell of the given type, row by row.
+func Find(board models.Board, t models.CellType) []models.Position {
+	var out []models.Position
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			if board.Grid[y][x].Type == t {
+				out = append(out, models.Position{X: x, Y: y})
+			}
+		}
+	}
+	return out
+}
+
+// IsType returns a goal that matches cells of the given type, e.g. the
+// exits.
+func IsType(board models.Board, t models.CellType) func(models.Position) bool {
+	return func(p models.Position) bool {
+		return board.Grid[p.Y][p.X].Type == t
+	}
+}
+
+// IsAt returns a goal that matches one position.
+func IsAt(target models.Position) func(models.Position) bool {
+	return func(p models.Position) bool { return p == target }
+}
+
+// Heuristic estimates the remaining cost from a position to the goal. It
+// must never overestimate for AStar to find the shortest path.
+type Heuristic func(models.Position) int
+
+// Zero is the heuristic that turns AStar into Dijkstra.
+func Zero(models.Position) int { return 0 }
+
+// Manhattan estimates the distance to the closest of the targets.
+func Manhattan(targets ...models.Position) Heuristic {
+	return func(p models.Position) int {
+		best := -1
+		for _, t := range targets {
+			d := abs(p.X-t.X) + abs(p.Y-t.Y)
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		return max(best, 0)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
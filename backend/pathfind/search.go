@@ -0,0 +1,185 @@
+package pathfind
+
+import (
+	"container/heap"
+	"maze-game/models"
+)
+
+// BFS returns the route with the fewest moves from a cell to the nearest
+// goal, both ends included, or nil if no goal can be reached.
+func BFS(g *Graph, from models.Position, goal func(models.Position) bool) []models.Position {
+	prev := make(map[models.Position]models.Position)
+	visited := map[models.Position]bool{from: true}
+	queue := []models.Position{from}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		if goal(cur) {
+			return walkBack(prev, from, cur)
+		}
+		for _, next := range g.Neighbors(cur) {
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			prev[next] = cur
+			queue = append(queue, next)
+		}
+	}
+
+	return nil
+}
+
+// Dijkstra returns the cheapest route from a cell to the nearest goal under
+// the graph's cell costs, and its cost. The route is nil and the cost -1
+// if no goal can be reached.
+func Dijkstra(g *Graph, from models.Position, goal func(models.Position) bool) ([]models.Position, int) {
+	return AStar(g, from, goal, Zero)
+}
+
+// AStar is Dijkstra guided by a heuristic.
+func AStar(g *Graph, from models.Position, goal func(models.Position) bool, h Heuristic) ([]models.Position, int) {
+	cost := map[models.Position]int{from: 0}
+	prev := make(map[models.Position]models.Position)
+	done := make(map[models.Position]bool)
+	open := &nodeQueue{{pos: from, priority: h(from)}}
+
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(node).pos
+		if done[cur] {
+			continue
+		}
+		done[cur] = true
+
+		if goal(cur) {
+			return walkBack(prev, from, cur), cost[cur]
+		}
+		for _, next := range g.Neighbors(cur) {
+			c := cost[cur] + g.cost(next)
+			if known, seen := cost[next]; seen && known <= c {
+				continue
+			}
+			cost[next] = c
+			prev[next] = cur
+			heap.Push(open, node{pos: next, priority: c + h(next)})
+		}
+	}
+
+	return nil, -1
+}
+
+// FloodFill returns every cell reachable from a cell, in BFS order.
+func FloodFill(g *Graph, from models.Position) []models.Position {
+	visited := map[models.Position]bool{from: true}
+	cells := []models.Position{from}
+	for i := 0; i < len(cells); i++ {
+		for _, next := range g.Neighbors(cells[i]) {
+			if !visited[next] {
+				visited[next] = true
+				cells = append(cells, next)
+			}
+		}
+	}
+	return cells
+}
+
+// DistanceMap returns the number of moves from a cell to every cell of the
+// board, indexed [y][x], with -1 for cells that cannot be reached.
+func DistanceMap(g *Graph, from models.Position) [][]int {
+	dist := make([][]int, g.Board.Rows)
+	for y := range dist {
+		dist[y] = make([]int, g.Board.Cols)
+		for x := range dist[y] {
+			dist[y][x] = -1
+		}
+	}
+
+	dist[from.Y][from.X] = 0
+	queue := []models.Position{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range g.Neighbors(cur) {
+			if dist[next.Y][next.X] < 0 {
+				dist[next.Y][next.X] = dist[cur.Y][cur.X] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return dist
+}
+
+// AllShortestPaths returns every route with the fewest moves between two
+// cells, at most limit of them (open rooms have a huge number). A limit of
+// 0 or less means no limit.
+func AllShortestPaths(g *Graph, from, to models.Position, limit int) [][]models.Position {
+	if !g.InBounds(to) {
+		return nil
+	}
+	dist := DistanceMap(g, from)
+	if dist[to.Y][to.X] < 0 {
+		return nil
+	}
+
+	// Walk back from the target through cells one move closer to the start.
+	var paths [][]models.Position
+	route := []models.Position{to}
+	var walk func(cur models.Position)
+	walk = func(cur models.Position) {
+		if limit > 0 && len(paths) >= limit {
+			return
+		}
+		if cur == from {
+			path := make([]models.Position, len(route))
+			for i, p := range route {
+				path[len(route)-1-i] = p
+			}
+			paths = append(paths, path)
+			return
+		}
+		for _, prev := range g.Neighbors(cur) {
+			if dist[prev.Y][prev.X] == dist[cur.Y][cur.X]-1 {
+				route = append(route, prev)
+				walk(prev)
+				route = route[:len(route)-1]
+			}
+		}
+	}
+	walk(to)
+	return paths
+}
+
+// walkBack follows prev links from the end back to the start and returns
+// the route from start to end.
+func walkBack(prev map[models.Position]models.Position, start, end models.Position) []models.Position {
+	path := []models.Position{end}
+	for cur := end; cur != start; {
+		cur = prev[cur]
+		path = append(path, cur)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// nodeQueue is a min-heap of cells by priority.
+type node struct {
+	pos      models.Position
+	priority int
+}
+
+type nodeQueue []node
+
+func (q nodeQueue) Len() int           { return len(q) }
+func (q nodeQueue) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q nodeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x any)        { *q = append(*q, x.(node)) }
+func (q *nodeQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,226 @@
+package pathfind
+
+import (
+	"slices"
+	"testing"
+
+	"maze-game/models"
+)
+
+// board builds a board from rows of symbols: '#' wall, '.' path, 'S'
+// start, 'E' exit, 'G' question wall, 'B' beyond the maze and '~' mud,
+// a path cell that costs 5 under mudCost.
+func board(rows ...string) models.Board {
+	b := models.Board{Rows: len(rows), Cols: len(rows[0])}
+	for y, row := range rows {
+		b.Grid = append(b.Grid, make([]models.Cell, len(row)))
+		for x, symbol := range row {
+			cell := models.Cell{Type: models.Path, Position: models.Position{X: x, Y: y}}
+			switch symbol {
+			case '#':
+				cell.Type = models.Wall
+			case 'S':
+				cell.Type = models.Start
+			case 'E':
+				cell.Type = models.End
+			case 'G':
+				cell.Type = models.Wall
+				cell.IsQuestionWall = true
+			case 'B':
+				cell.Type = models.Beyond
+			}
+			b.Grid[y][x] = cell
+		}
+	}
+	return b
+}
+
+// mudCost prices the mud cells of the rows a board was built from.
+func mudCost(rows ...string) func(models.Cell) int {
+	return func(cell models.Cell) int {
+		if rows[cell.Position.Y][cell.Position.X] == '~' {
+			return 5
+		}
+		return 1
+	}
+}
+
+func pos(x, y int) models.Position { return models.Position{X: x, Y: y} }
+
+// checkRoute fails unless route runs from one cell to another in single
+// passable steps.
+func checkRoute(t *testing.T, g *Graph, route []models.Position, from, to models.Position) {
+	t.Helper()
+	if route[0] != from || route[len(route)-1] != to {
+		t.Fatalf("route %v does not run from %v to %v", route, from, to)
+	}
+	for i := 1; i < len(route); i++ {
+		a, b := route[i-1], route[i]
+		if abs(a.X-b.X)+abs(a.Y-b.Y) != 1 || !g.CanEnter(b) {
+			t.Fatalf("route %v: bad step from %v to %v", route, a, b)
+		}
+	}
+}
+
+func TestBFS(t *testing.T) {
+	tests := []struct {
+		name  string
+		board models.Board
+		moves int // -1 if the exit can't be reached
+	}{
+		{"corridor", board("S...E"), 4},
+		{"around a wall", board(
+			"S#E",
+			".#.",
+			"..."), 6},
+		{"shortest of two ways", board(
+			"S...",
+			".##.",
+			"...E"), 5},
+		{"walled off", board(
+			"S#E",
+			".#.",
+			".#."), -1},
+		{"through a question wall", board("SGE"), 2},
+		{"not beyond the maze", board("SBE"), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.board)
+			start, exit := Find(tt.board, models.Start)[0], Find(tt.board, models.End)[0]
+			route := BFS(g, start, IsType(tt.board, models.End))
+			if tt.moves < 0 {
+				if route != nil {
+					t.Fatalf("got route %v, want none", route)
+				}
+				return
+			}
+			checkRoute(t, g, route, start, exit)
+			if len(route)-1 != tt.moves {
+				t.Errorf("got %d moves, want %d", len(route)-1, tt.moves)
+			}
+		})
+	}
+}
+
+func TestDijkstraAndAStar(t *testing.T) {
+	around := []string{
+		"S~~E",
+		".##.",
+		"....",
+	}
+	through := []string{
+		"S~E",
+		".#.",
+		".#.",
+		".#.",
+		"...",
+	}
+	tests := []struct {
+		name string
+		rows []string
+		mud  bool // Whether mud costs extra
+		want int  // -1 if the exit can't be reached
+	}{
+		{"unit costs", around, false, 3},
+		{"detour around mud", around, true, 7},
+		{"mud is cheaper than the long way", through, true, 6},
+		{"unreachable", []string{"S#E"}, false, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := board(tt.rows...)
+			g := New(b)
+			if tt.mud {
+				g.Cost = mudCost(tt.rows...)
+			}
+			start, exit := Find(b, models.Start)[0], Find(b, models.End)[0]
+			goal := IsAt(exit)
+
+			searches := map[string]func() ([]models.Position, int){
+				"dijkstra": func() ([]models.Position, int) { return Dijkstra(g, start, goal) },
+				"astar":    func() ([]models.Position, int) { return AStar(g, start, goal, Manhattan(exit)) },
+			}
+			for name, search := range searches {
+				route, cost := search()
+				if cost != tt.want {
+					t.Errorf("%s: got cost %d, want %d", name, cost, tt.want)
+				}
+				if tt.want < 0 {
+					if route != nil {
+						t.Errorf("%s: got route %v, want none", name, route)
+					}
+					continue
+				}
+				checkRoute(t, g, route, start, exit)
+			}
+		})
+	}
+}
+
+func TestDistanceMap(t *testing.T) {
+	b := board(
+		"S.#",
+		"#..",
+		"#.#",
+		"..B")
+	want := [][]int{
+		{0, 1, -1},
+		{-1, 2, 3},
+		{-1, 3, -1},
+		{5, 4, -1},
+	}
+	got := DistanceMap(New(b), pos(0, 0))
+	for y := range want {
+		if !slices.Equal(got[y], want[y]) {
+			t.Errorf("row %d: got %v, want %v", y, got[y], want[y])
+		}
+	}
+}
+
+func TestAllShortestPaths(t *testing.T) {
+	room := board(
+		"S..",
+		"...",
+		"..E")
+	tests := []struct {
+		name  string
+		board models.Board
+		limit int
+		want  int
+	}{
+		{"every path through a room", room, 0, 6},
+		{"limited", room, 2, 2},
+		{"limit above the count", room, 10, 6},
+		{"single corridor", board(
+			"S.#",
+			"#.E"), 0, 1},
+		{"unreachable", board("S#E"), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(tt.board)
+			start, exit := Find(tt.board, models.Start)[0], Find(tt.board, models.End)[0]
+			paths := AllShortestPaths(g, start, exit, tt.limit)
+			if len(paths) != tt.want {
+				t.Fatalf("got %d paths, want %d", len(paths), tt.want)
+			}
+			shortest := len(BFS(g, start, IsAt(exit)))
+			seen := make(map[string]bool)
+			for _, path := range paths {
+				checkRoute(t, g, path, start, exit)
+				if len(path) != shortest {
+					t.Errorf("path %v is not a shortest path", path)
+				}
+				key := ""
+				for _, p := range path {
+					key += string(rune('a'+p.X)) + string(rune('a'+p.Y))
+				}
+				if seen[key] {
+					t.Errorf("path %v returned twice", path)
+				}
+				seen[key] = true
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+
+	"maze-game/models"
+)
+
+// Default and maximum frames per second of an exported run.
+const (
+	DefaultFPS = 10
+	MaxFPS     = 50
+)
+
+// How long the final frame is held, in 100ths of a second.
+const finalFrameDelay = 300
+
+// GIFOptions configures an animated export.
+type GIFOptions struct {
+	CellSize int
+	FPS      int
+}
+
+var gifPalette = color.Palette{WallColor, PathColor, StartColor, ExitColor, PlayerColor}
+
+// GIF renders the player's path through the board as an animated GIF.
+// The first frame is the whole maze; every following frame only draws the
+// newly visited cell on top of it, which keeps large runs small.
+func GIF(w io.Writer, board models.Board, path []models.Position, opts GIFOptions) error {
+	cellSize := clampCellSize(board, opts.CellSize)
+	fps := opts.FPS
+	if fps <= 0 {
+		fps = DefaultFPS
+	}
+	fps = min(fps, MaxFPS)
+	delay := max(100/fps, 2)
+
+	bounds := image.Rect(0, 0, board.Cols*cellSize, board.Rows*cellSize)
+	first := image.NewPaletted(bounds, gifPalette)
+	drawBoard(first, board, cellSize)
+
+	anim := &gif.GIF{
+		Image:    []*image.Paletted{first},
+		Delay:    []int{delay},
+		Disposal: []byte{gif.DisposalNone},
+		Config: image.Config{
+			ColorModel: gifPalette,
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+
+	for i, pos := range path {
+		// Skip moves that did not change the position (e.g. bumping a wall).
+		if i > 0 && pos == path[i-1] {
+			continue
+		}
+		// Keep the exit visible once the player steps onto it.
+		c := PlayerColor
+		if board.Grid[pos.Y][pos.X].Type == models.End {
+			c = ExitColor
+		}
+		frame := image.NewPaletted(cellRect(pos, cellSize), gifPalette)
+		fillCell(frame, pos, cellSize, c)
+
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+	anim.Delay[len(anim.Delay)-1] = finalFrameDelay
+
+	return gif.EncodeAll(w, anim)
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"maze-game/models"
+)
+
+// SolutionColor is used for the optional solution overlay.
+var SolutionColor = color.RGBA{0x2f, 0x81, 0xf7, 0xff}
+
+// Options configures a still image of a board.
+type Options struct {
+	CellSize int
+	// Solution, if set, is drawn on top of the maze.
+	Solution []models.Position
+}
+
+// Image draws the board (and solution, if any) into an RGBA image.
+func Image(board models.Board, opts Options) *image.RGBA {
+	cellSize := clampCellSize(board, opts.CellSize)
+	img := image.NewRGBA(image.Rect(0, 0, board.Cols*cellSize, board.Rows*cellSize))
+	drawBoard(img, board, cellSize)
+
+	// The overlay is an inset square so the cell type stays visible.
+	inset := cellSize / 4
+	for _, pos := range opts.Solution {
+		rect := cellRect(pos, cellSize).Inset(inset)
+		fillRect(img, rect, SolutionColor)
+	}
+
+	return img
+}
+
+// PNG encodes the board as a PNG image.
+func PNG(w io.Writer, board models.Board, opts Options) error {
+	return png.Encode(w, Image(board, opts))
+}
+
+// SVG writes the board as a scalable vector image. Walls are emitted as
+// rectangles on a path-colored background and the solution as a polyline
+// through the cell centers.
+func SVG(w io.Writer, board models.Board, opts Options) error {
+	cellSize := clampCellSize(board, opts.CellSize)
+	width, height := board.Cols*cellSize, board.Rows*cellSize
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", width, height, hex(PathColor))
+
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			cell := board.Grid[y][x]
+			c := cellColor(cell)
+			if c == PathColor {
+				continue
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+				x*cellSize, y*cellSize, cellSize, cellSize, hex(c))
+		}
+	}
+
+	if len(opts.Solution) > 0 {
+		points := make([]string, len(opts.Solution))
+		for i, pos := range opts.Solution {
+			points[i] = fmt.Sprintf("%g,%g", (float64(pos.X)+0.5)*float64(cellSize), (float64(pos.Y)+0.5)*float64(cellSize))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linecap="round" stroke-linejoin="round"/>`+"\n",
+			strings.Join(points, " "), hex(SolutionColor), float64(cellSize)/2)
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
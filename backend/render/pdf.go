@@ -0,0 +1,182 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"maze-game/models"
+)
+
+// A4 page size and margin in PDF points.
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	pageMargin = 50.0
+	headerSize = 60.0 // Space reserved for the title and subtitle.
+)
+
+// Puzzle is a single maze page of a puzzle book.
+type Puzzle struct {
+	Title    string
+	Subtitle string
+	Board    models.Board
+	Solution []models.Position
+}
+
+// PuzzleBook writes a printable PDF with one maze per page, followed by an
+// answer key that repeats every maze with its solution drawn in.
+func PuzzleBook(w io.Writer, puzzles []Puzzle) error {
+	var pages []string
+	for _, p := range puzzles {
+		pages = append(pages, puzzlePage(p.Title, p.Subtitle, p.Board, nil))
+	}
+	for i, p := range puzzles {
+		title := "Answer Key - " + p.Title
+		if i > 0 {
+			title = "Answer - " + p.Title
+		}
+		pages = append(pages, puzzlePage(title, p.Subtitle, p.Board, p.Solution))
+	}
+
+	return writePDF(w, pages)
+}
+
+// puzzlePage builds the content stream for a single page.
+func puzzlePage(title, subtitle string, board models.Board, solution []models.Position) string {
+	var b strings.Builder
+
+	// Header
+	fmt.Fprintf(&b, "BT /F1 20 Tf %.2f %.2f Td (%s) Tj ET\n", pageMargin, pageHeight-pageMargin-10, pdfString(title))
+	fmt.Fprintf(&b, "BT /F1 10 Tf %.2f %.2f Td (%s) Tj ET\n", pageMargin, pageHeight-pageMargin-28, pdfString(subtitle))
+
+	if board.Rows == 0 || board.Cols == 0 {
+		return b.String()
+	}
+
+	// Fit the maze into the space below the header and center it.
+	areaW := pageWidth - 2*pageMargin
+	areaH := pageHeight - 2*pageMargin - headerSize
+	cell := min(areaW/float64(board.Cols), areaH/float64(board.Rows))
+	left := pageMargin + (areaW-cell*float64(board.Cols))/2
+	top := pageHeight - pageMargin - headerSize
+
+	rect := func(x, y, width int) string {
+		return fmt.Sprintf("%.2f %.2f %.2f %.2f re\n", left+float64(x)*cell, top-float64(y+1)*cell, float64(width)*cell, cell)
+	}
+
+	// Start and exit in light grey so they survive black and white printing.
+	b.WriteString("0.8 g\n")
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			if t := board.Grid[y][x].Type; t == models.Start || t == models.End {
+				b.WriteString(rect(x, y, 1))
+			}
+		}
+	}
+	b.WriteString("f\n")
+
+	// Walls in black, merged into horizontal runs to keep the file small.
+	b.WriteString("0 g\n")
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; {
+			if board.Grid[y][x].Type != models.Wall && board.Grid[y][x].Type != models.Beyond {
+				x++
+				continue
+			}
+			run := x
+			for run < board.Cols && (board.Grid[y][run].Type == models.Wall || board.Grid[y][run].Type == models.Beyond) {
+				run++
+			}
+			b.WriteString(rect(x, y, run-x))
+			x = run
+		}
+	}
+	b.WriteString("f\n")
+
+	// Outline around the whole maze.
+	fmt.Fprintf(&b, "1 w %.2f %.2f %.2f %.2f re S\n", left, top-cell*float64(board.Rows), cell*float64(board.Cols), cell*float64(board.Rows))
+
+	if len(solution) > 0 {
+		r, g, bl, _ := SolutionColor.RGBA()
+		fmt.Fprintf(&b, "%.3f %.3f %.3f RG %.2f w 1 J 1 j\n", float64(r)/0xffff, float64(g)/0xffff, float64(bl)/0xffff, cell/3)
+		for i, pos := range solution {
+			op := "l"
+			if i == 0 {
+				op = "m"
+			}
+			fmt.Fprintf(&b, "%.2f %.2f %s\n", left+(float64(pos.X)+0.5)*cell, top-(float64(pos.Y)+0.5)*cell, op)
+		}
+		b.WriteString("S\n")
+	}
+
+	return b.String()
+}
+
+// writePDF assembles a minimal PDF 1.4 document from page content streams.
+// Object layout: 1 catalog, 2 page tree, 3 font, then a page object and its
+// content stream for every page.
+func writePDF(w io.Writer, pages []string) error {
+	var buf bytes.Buffer
+	offsets := []int{0} // Object 0 is the free-list head.
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets)-1, body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+2*i))
+
+		var stream bytes.Buffer
+		zw := zlib.NewWriter(&stream)
+		if _, err := zw.Write([]byte(content)); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, off := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfString escapes text for a PDF literal string. We don't embed fonts or
+// set an encoding, so anything outside printable ASCII is replaced.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"maze-game/models"
+)
+
+// Colors follow the frontend theme (see frontend/src/index.css).
+var (
+	WallColor   = color.RGBA{0x2b, 0x1d, 0x38, 0xff}
+	PathColor   = color.RGBA{0x16, 0x1b, 0x22, 0xff}
+	StartColor  = color.RGBA{0x1f, 0x5c, 0x28, 0xff}
+	ExitColor   = color.RGBA{0x3f, 0xb9, 0x50, 0xff}
+	PlayerColor = color.RGBA{0xa3, 0x71, 0xf7, 0xff}
+)
+
+// Default and maximum size of a single maze cell in pixels.
+const (
+	DefaultCellSize = 8
+	MaxCellSize     = 64
+)
+
+// MaxImageSize is the largest width or height of a rendered board in
+// pixels. Large boards get smaller cells to stay within it.
+const MaxImageSize = 4096
+
+// cellColor picks the base color for a cell type.
+func cellColor(cell models.Cell) color.Color {
+	switch cell.Type {
+	case models.Wall, models.Beyond:
+		return WallColor
+	case models.Start:
+		return StartColor
+	case models.End:
+		return ExitColor
+	default:
+		return PathColor
+	}
+}
+
+// drawBoard paints every cell of the board onto img.
+func drawBoard(img draw.Image, board models.Board, cellSize int) {
+	for y := 0; y < board.Rows; y++ {
+		for x := 0; x < board.Cols; x++ {
+			fillCell(img, models.Position{X: x, Y: y}, cellSize, cellColor(board.Grid[y][x]))
+		}
+	}
+}
+
+// fillCell paints a single cell.
+func fillCell(img draw.Image, pos models.Position, cellSize int, c color.Color) {
+	fillRect(img, cellRect(pos, cellSize), c)
+}
+
+func fillRect(img draw.Image, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func cellRect(pos models.Position, cellSize int) image.Rectangle {
+	return image.Rect(pos.X*cellSize, pos.Y*cellSize, (pos.X+1)*cellSize, (pos.Y+1)*cellSize)
+}
+
+// clampCellSize keeps user supplied sizes within sane limits, and the
+// whole board within MaxImageSize.
+func clampCellSize(board models.Board, size int) int {
+	if size <= 0 {
+		size = DefaultCellSize
+	}
+	size = min(size, MaxCellSize, MaxImageSize/max(board.Rows, board.Cols, 1))
+	return max(size, 1)
+}
@@ -0,0 +1,147 @@
+package socket
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"maze-game/game"
+	"maze-game/models"
+)
+
+// GenerateHandler streams a maze being generated, step by step. The maze is
+// picked by share code or by the same settings as a new game; once it is
+// done the client gets the board and its code, ready for /api/game/start.
+// URL: /ws/generate?rows={ROWS}&cols={COLS}&seed={SEED}&algorithm={ALGORITHM}&braiding={BRAIDING}&speed={SPEED}
+// URL: /ws/generate?code={CODE}&speed={SPEED}
+func GenerateHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer ws.Close()
+
+	query := r.URL.Query()
+	opts, msg := generateOptions(query)
+	if msg != "" {
+		writeMessage(ws, "error", msg)
+		return
+	}
+
+	speed := 1.0
+	if s, err := strconv.ParseFloat(query.Get("speed"), 64); err == nil && s > 0 {
+		speed = s
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	messages := readMessages(ws, done)
+
+	if err := writeMessage(ws, "generate_start", map[string]interface{}{
+		"rows":      opts.Maze.Rows,
+		"cols":      opts.Maze.Cols,
+		"seed":      opts.Maze.Seed,
+		"algorithm": opts.Maze.Algorithm,
+		"braiding":  opts.Maze.Braiding,
+		"speed":     speed,
+	}); err != nil {
+		return
+	}
+
+	// Generation runs on its own goroutine and waits for the client to
+	// take every step, like the solver stream.
+	steps := make(chan game.GenerationStep)
+	var board models.Board
+	go func() {
+		defer close(steps)
+		maze := opts.Maze
+		maze.OnStep = func(s game.GenerationStep) {
+			select {
+			case steps <- s:
+			case <-done:
+			}
+		}
+		board = game.GenerateMazeWithOptions(maze)
+	}()
+
+	playStream(ws, messages, stream{
+		interval: baseStepInterval,
+		stepType: "step",
+		endType:  "generate_end",
+		next: func(index int) (any, bool) {
+			step, ok := <-steps
+			if !ok {
+				return nil, false
+			}
+			return map[string]interface{}{
+				"index": index,
+				"phase": step.Phase,
+				"type":  step.Type,
+				"cells": step.Cells,
+			}, true
+		},
+		// Generation is done; board was set before steps closed.
+		end: func(steps int) any {
+			return map[string]interface{}{
+				"board": board,
+				"code":  game.EncodeCode(opts),
+				"steps": steps,
+			}
+		},
+	}, speed)
+}
+
+// generateOptions reads the maze to generate from the query. It returns an
+// error message when the settings are invalid.
+func generateOptions(query url.Values) (game.GameOptions, string) {
+	get := query.Get
+
+	var opts game.GameOptions
+	if code := get("code"); code != "" {
+		decoded, err := game.DecodeCode(code)
+		if err != nil {
+			return opts, err.Error()
+		}
+		opts = decoded
+	} else {
+		opts.Maze = game.MazeOptions{Rows: 10, Cols: 10, Algorithm: get("algorithm"), Braiding: game.DefaultBraiding}
+		if v, err := strconv.Atoi(get("rows")); err == nil && v > 0 {
+			opts.Maze.Rows = v
+		}
+		if v, err := strconv.Atoi(get("cols")); err == nil && v > 0 {
+			opts.Maze.Cols = v
+		}
+		if v, err := strconv.ParseInt(get("seed"), 10, 64); err == nil {
+			opts.Maze.Seed = v
+		}
+		if v := get("braiding"); v != "" {
+			b, err := strconv.ParseFloat(v, 64)
+			if err != nil || b < 0 || b > 1 {
+				return opts, "Braiding must be between 0 and 1"
+			}
+			// Whole percent, as in share codes.
+			opts.Maze.Braiding = math.Round(b*100) / 100
+		}
+		if opts.Maze.Rows > game.MaxBoardSize || opts.Maze.Cols > game.MaxBoardSize {
+			return opts, "Board is too large"
+		}
+		if !game.ValidAlgorithm(opts.Maze.Algorithm) {
+			return opts, "Unknown algorithm"
+		}
+	}
+
+	// Fill in the defaults now so the final code matches the maze shown.
+	if opts.Maze.Seed == 0 {
+		opts.Maze.Seed = game.RandomSeed()
+	}
+	if opts.Maze.Algorithm == "" {
+		opts.Maze.Algorithm = game.AlgorithmBacktracking
+	}
+	if opts.Mode == "" || opts.Mode == game.ModeDaily {
+		opts.Mode = game.ModeClassic
+	}
+	return opts, ""
+}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
 	"maze-game/game"
+	"maze-game/models"
 
 	"github.com/gorilla/websocket"
 )
@@ -18,8 +20,12 @@ var upgrader = websocket.Upgrader{
 }
 
 type WSMoveRequest struct {
-	Type      string `json:"type"` // "move"
+	Type      string `json:"type"` // "move", "answer", "pause", "resume" or "hint"
 	Direction string `json:"direction"`
+	Cells     int    `json:"cells,omitempty"` // How far ahead a hint shows the route
+	// Answer to the question the last move found.
+	QuestionID int           `json:"question_id,omitempty"`
+	Answer     models.Answer `json:"answer,omitempty"`
 }
 
 type WSMoveResponse struct {
@@ -51,26 +57,71 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Player connected to game %s", gameID)
 
+	// Reads happen on their own goroutine so timed games can push clock
+	// updates while waiting for the next move.
+	done := make(chan struct{})
+	defer close(done)
+	messages := readMessages(ws, done)
+
+	// The ticker pushes clock updates for timed games and tells the client
+	// when the server changed the game on its own (timeout, auto-resume).
+	ticker := time.NewTicker(clockInterval)
+	defer ticker.Stop()
+	lastStatus := game.Snapshot(gameInstance).Status
+
 	// Listen for messages
 	for {
-		// Read message as JSON
-		_, message, err := ws.ReadMessage()
-		if err != nil {
-			log.Println("Read error:", err)
-			break
-		}
+		var response WSMoveResponse
 
-		var req WSMoveRequest
-		if err := json.Unmarshal(message, &req); err != nil {
-			log.Println("JSON error:", err)
-			continue
-		}
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var req WSMoveRequest
+			if err := json.Unmarshal(message, &req); err != nil {
+				log.Println("JSON error:", err)
+				continue
+			}
 
-		if req.Type == "move" {
-			// Process move
-			result, _, err := game.MovePlayer(gameInstance, req.Direction)
+			var result string
+			var hint *game.Hint
+			var question *models.PublicQuestion
+			var opened *models.Position
+			var err error
+			switch req.Type {
+			case "move":
+				// Process move
+				var qID int
+				result, qID, err = game.MovePlayer(gameInstance, req.Direction)
+				if q, ok := game.GetQuestion(qID); ok && result == "QuestionFound" {
+					question = q.Public()
+				}
+			case "answer":
+				var answer *game.AnswerResult
+				answer, err = game.AnswerQuestion(gameInstance, req.QuestionID, req.Answer)
+				if err == nil {
+					result = "Wrong"
+					if answer.Correct {
+						result = "Correct"
+					}
+					opened = answer.Opened
+				}
+			case "pause":
+				result = "Paused"
+				_, err = game.PauseGame(gameInstance)
+			case "resume":
+				result = "Resumed"
+				_, err = game.ResumeGame(gameInstance)
+			case "hint":
+				result = "Hint"
+				hint, err = game.GetHint(gameInstance, req.Cells)
+			default:
+				continue
+			}
 
-			response := WSMoveResponse{
+			response = WSMoveResponse{
 				Type: "update",
 			}
 
@@ -78,24 +129,118 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 				response.Type = "error"
 				response.Payload = err.Error()
 			} else {
-				// OPTIMIZATION: Send only what changed (Player & Status).
-				// The Board is static and large, sending it every 100ms kills performance.
-
-				payload := map[string]interface{}{
-					"result": result,
-					// "game_state": gameInstance, // Too big!
-					"player": gameInstance.Player,
-					"status": gameInstance.Status,
-				}
+				snapshot := game.Snapshot(gameInstance)
+				lastStatus = snapshot.Status
+				payload := updatePayload(result, snapshot)
+				payload.Hint = hint
+				payload.Question = question
+				payload.Opened = opened
 				response.Payload = payload
 			}
 
-			// Write response
-			responseBytes, _ := json.Marshal(response)
-			if err := ws.WriteMessage(websocket.TextMessage, responseBytes); err != nil {
-				log.Println("Write error:", err)
-				break
+		case <-ticker.C:
+			snapshot := game.Snapshot(gameInstance)
+			var result string
+			switch {
+			case snapshot.Reason == game.ReasonTimeout && lastStatus != snapshot.Status:
+				result = "Timeout"
+			case lastStatus == "PAUSED" && snapshot.Status == "ACTIVE":
+				// Ran out of pause time.
+				result = "Resumed"
+			case snapshot.Status == "ACTIVE" && snapshot.TimeLimitMs > 0:
+				result = "Tick"
+			default:
+				continue
 			}
+			lastStatus = snapshot.Status
+			response = WSMoveResponse{Type: "update", Payload: updatePayload(result, snapshot)}
+		}
+
+		// Write response
+		responseBytes, _ := json.Marshal(response)
+		if err := ws.WriteMessage(websocket.TextMessage, responseBytes); err != nil {
+			log.Println("Write error:", err)
+			return
+		}
+	}
+}
+
+// How often the server checks the clock of a connected game.
+const clockInterval = time.Second
+
+// WSUpdatePayload is the payload of an "update" message.
+// OPTIMIZATION: Send only what changed (Player, Status & Clock).
+// The Board is static and large, sending it every 100ms kills performance.
+type WSUpdatePayload struct {
+	Result          string        `json:"result"`
+	Player          models.Player `json:"player"`
+	Status          string        `json:"status"`
+	ElapsedMs       int64         `json:"elapsed_ms"`
+	TimeRemainingMs *int64        `json:"time_remaining_ms,omitempty"`
+	Reason          string        `json:"reason,omitempty"`
+	// Let the client show how the run placed once it's over.
+	Rank  int          `json:"rank,omitempty"`
+	Stats *WSGameStats `json:"stats,omitempty"` // Only once the game is won
+	// Set by the message that produced them.
+	Hint     *game.Hint             `json:"hint,omitempty"`
+	Question *models.PublicQuestion `json:"question,omitempty"`
+	Opened   *models.Position       `json:"opened,omitempty"`
+}
+
+// WSGameStats sums up a won game.
+type WSGameStats struct {
+	Moves int `json:"moves"`
+	Bumps int `json:"bumps"`
+	Par   int `json:"par"`
+	Stars int `json:"stars"`
+	Score int `json:"score"`
+	// Itemized points, one entry per scoring rule.
+	ScoreBreakdown []models.ScoreItem `json:"score_breakdown"`
+}
+
+// updatePayload builds the payload of an "update" message.
+func updatePayload(result string, gameInstance *models.GameState) *WSUpdatePayload {
+	payload := &WSUpdatePayload{
+		Result: result,
+		// "game_state": gameInstance, // Too big!
+		Player:          gameInstance.Player,
+		Status:          gameInstance.Status,
+		ElapsedMs:       gameInstance.ElapsedMs,
+		TimeRemainingMs: gameInstance.TimeRemainingMs,
+		Reason:          gameInstance.Reason,
+		Rank:            gameInstance.Rank,
+	}
+	if gameInstance.Status == "WON" {
+		payload.Stats = &WSGameStats{
+			Moves:          gameInstance.Player.Moves,
+			Bumps:          gameInstance.Player.Bumps,
+			Par:            gameInstance.Par,
+			Stars:          gameInstance.Stars,
+			Score:          gameInstance.Player.Score,
+			ScoreBreakdown: gameInstance.ScoreBreakdown,
 		}
 	}
+	return payload
+}
+
+// readMessages pumps incoming messages into a channel, which is closed when
+// the connection fails. Stops early once done is closed.
+func readMessages(ws *websocket.Conn, done <-chan struct{}) <-chan []byte {
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		for {
+			_, message, err := ws.ReadMessage()
+			if err != nil {
+				log.Println("Read error:", err)
+				return
+			}
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return messages
 }
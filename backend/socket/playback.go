@@ -0,0 +1,95 @@
+package socket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// stream is a sequence of messages played back to a client, one per tick,
+// at a speed the client controls (see playStream).
+type stream struct {
+	// Time between steps at speed 1.0.
+	interval time.Duration
+	// Message types of a step and of the closing message.
+	stepType, endType string
+	// next returns the payload of the step at index, or false once there
+	// are no more.
+	next func(index int) (any, bool)
+	// end returns the payload of the closing message, given the number of
+	// steps sent.
+	end func(steps int) any
+	// seek jumps to a step and returns the index it moved to and the payload
+	// of the "seek" reply. Only set for streams that can go back.
+	seek func(index int) (int, any)
+}
+
+// tick is the time between steps at a speed.
+func (s stream) tick(speed float64) time.Duration {
+	return max(time.Duration(float64(s.interval)/speed), time.Millisecond)
+}
+
+// playStream sends a stream to the client until it ends or the connection
+// closes. The client steers it with WSReplayControl messages: play, pause,
+// speed and, if the stream supports it, seek. Playing a finished stream
+// that can seek starts it over.
+func playStream(ws *websocket.Conn, messages <-chan []byte, s stream, speed float64) {
+	index := 0
+	playing, finished := true, false
+	ticker := time.NewTicker(s.tick(speed))
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var c WSReplayControl
+			if err := json.Unmarshal(message, &c); err != nil {
+				log.Println("JSON error:", err)
+				continue
+			}
+			switch c.Type {
+			case "play":
+				if finished && s.seek != nil {
+					index, finished = 0, false
+				}
+				playing = true
+			case "pause":
+				playing = false
+			case "seek":
+				if s.seek != nil {
+					var payload any
+					index, payload = s.seek(c.Index)
+					finished = false
+					err = writeMessage(ws, "seek", payload)
+				}
+			case "speed":
+				if c.Speed > 0 {
+					speed = c.Speed
+					ticker.Reset(s.tick(speed))
+				}
+			}
+		case <-ticker.C:
+			if !playing || finished {
+				continue
+			}
+			if payload, ok := s.next(index); ok {
+				err = writeMessage(ws, s.stepType, payload)
+				index++
+			} else {
+				finished = true
+				err = writeMessage(ws, s.endType, s.end(index))
+			}
+		}
+
+		if err != nil {
+			log.Println("Write error:", err)
+			return
+		}
+	}
+}
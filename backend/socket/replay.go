@@ -0,0 +1,99 @@
+package socket
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"maze-game/game"
+
+	"github.com/gorilla/websocket"
+)
+
+// Time between frames at speed 1.0.
+const baseFrameInterval = 250 * time.Millisecond
+
+// WSReplayControl is a playback command sent by the client.
+type WSReplayControl struct {
+	Type  string  `json:"type"` // "play", "pause", "seek" or "speed"
+	Index int     `json:"index"`
+	Speed float64 `json:"speed"`
+}
+
+// ReplayHandler re-emits the moves of a finished game.
+// URL: /ws/replay?id={GAME_ID}&speed={SPEED}
+func ReplayHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer ws.Close()
+
+	gameID := r.URL.Query().Get("id")
+	gameInstance, exists := game.GetGame(gameID)
+	if !exists {
+		writeMessage(ws, "error", "Game not found")
+		return
+	}
+	if !game.Finished(gameInstance) {
+		writeMessage(ws, "error", "Replay is only available for finished games")
+		return
+	}
+
+	speed := 1.0
+	if s, err := strconv.ParseFloat(r.URL.Query().Get("speed"), 64); err == nil && s > 0 {
+		speed = s
+	}
+
+	replay := game.BuildReplay(gameInstance)
+	total := len(replay.Moves)
+
+	// Read control messages on a separate goroutine so the playback loop
+	// can keep ticking while it waits for them.
+	done := make(chan struct{})
+	defer close(done)
+	messages := readMessages(ws, done)
+
+	if err := writeMessage(ws, "replay_start", map[string]interface{}{
+		"board": replay.Board,
+		"start": replay.Start,
+		"total": total,
+		"speed": speed,
+	}); err != nil {
+		return
+	}
+
+	playStream(ws, messages, stream{
+		interval: baseFrameInterval,
+		stepType: "frame",
+		endType:  "replay_end",
+		next: func(index int) (any, bool) {
+			if index >= total {
+				return nil, false
+			}
+			return map[string]interface{}{
+				"index": index,
+				"move":  replay.Moves[index],
+			}, true
+		},
+		end: func(int) any {
+			return map[string]interface{}{"status": replay.Status}
+		},
+		seek: func(index int) (int, any) {
+			index = max(0, min(index, total))
+			return index, map[string]interface{}{
+				"index":    index,
+				"position": replay.PositionAt(index),
+			}
+		},
+	}, speed)
+}
+
+// writeMessage sends a typed message in the same envelope as game updates.
+func writeMessage(ws *websocket.Conn, msgType string, payload interface{}) error {
+	responseBytes, _ := json.Marshal(WSMoveResponse{Type: msgType, Payload: payload})
+	return ws.WriteMessage(websocket.TextMessage, responseBytes)
+}
@@ -0,0 +1,117 @@
+package socket
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"maze-game/auth"
+	"maze-game/game"
+	"maze-game/models"
+	"maze-game/pathfind"
+)
+
+// Time between solver steps at speed 1.0.
+const baseStepInterval = 25 * time.Millisecond
+
+// SolveHandler streams a solver exploring a game's board, from the start
+// to the exit. Open for finished games; admins can watch any game.
+// URL: /ws/solve?id={GAME_ID}&algorithm={ALGORITHM}&speed={SPEED}&token={ADMIN_TOKEN}
+func SolveHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer ws.Close()
+
+	query := r.URL.Query()
+	gameInstance, exists := game.GetGame(query.Get("id"))
+	if !exists {
+		writeMessage(ws, "error", "Game not found")
+		return
+	}
+	admin := auth.IsAdmin(r) || auth.ValidToken(query.Get("token"))
+	if !admin && !game.Finished(gameInstance) {
+		writeMessage(ws, "error", "Solver is only available for finished games")
+		return
+	}
+
+	algorithm := query.Get("algorithm")
+	if algorithm == "" {
+		algorithm = pathfind.SolverBFS
+	}
+	if !pathfind.ValidSolver(algorithm) {
+		writeMessage(ws, "error", "Unknown algorithm: "+algorithm)
+		return
+	}
+
+	speed := 1.0
+	if s, err := strconv.ParseFloat(query.Get("speed"), 64); err == nil && s > 0 {
+		speed = s
+	}
+
+	board := game.SnapshotBoard(gameInstance)
+	starts := pathfind.Find(board, models.Start)
+	if len(starts) == 0 {
+		writeMessage(ws, "error", "Board has no start")
+		return
+	}
+	exits := pathfind.Find(board, models.End)
+
+	done := make(chan struct{})
+	defer close(done)
+	messages := readMessages(ws, done)
+
+	if err := writeMessage(ws, "solve_start", map[string]interface{}{
+		"board":     board,
+		"start":     starts[0],
+		"algorithm": algorithm,
+		"speed":     speed,
+	}); err != nil {
+		return
+	}
+
+	// The solver runs on its own goroutine and hands over one step at a
+	// time, so it only gets as far as the client has watched.
+	steps := make(chan pathfind.Step)
+	var path []models.Position
+	go func() {
+		defer close(steps)
+		path, _ = pathfind.Explore(pathfind.New(board), algorithm, starts[0], pathfind.IsType(board, models.End),
+			pathfind.Manhattan(exits...), func(s pathfind.Step) bool {
+				select {
+				case steps <- s:
+					return true
+				case <-done:
+					return false
+				}
+			})
+	}()
+
+	playStream(ws, messages, stream{
+		interval: baseStepInterval,
+		stepType: "step",
+		endType:  "solve_end",
+		next: func(index int) (any, bool) {
+			step, ok := <-steps
+			if !ok {
+				return nil, false
+			}
+			return map[string]interface{}{
+				"index":    index,
+				"visited":  step.Visited,
+				"frontier": step.Frontier,
+			}, true
+		},
+		// The solver is done; path was set before steps closed.
+		end: func(steps int) any {
+			return map[string]interface{}{
+				"found": path != nil,
+				"path":  path,
+				"steps": steps,
+			}
+		},
+	}, speed)
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"fmt"
+	"maze-game/models"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QuestionBank is a validated set of questions, indexed for lookups. It is
+// never changed after it is built.
+type QuestionBank struct {
+	questions    []models.Question // In file order
+	sources      []Source          // Where each question came from
+	byID         map[int]int       // ID -> index in questions
+	byDifficulty map[string][]int
+	byCategory   map[string][]int
+	// Questions dropped by a reload. Games started before it may still
+	// show them, so Get keeps finding them; new games never get them.
+	retired map[int]models.Question
+}
+
+// Source is where a question was read from.
+type Source struct {
+	File  string
+	Entry int // 1-based position in the file, not counting a CSV header
+}
+
+func (s Source) String() string {
+	if s.File == "" {
+		return fmt.Sprintf("entry %d", s.Entry)
+	}
+	if fileFormat(s.File) == formatCSV {
+		// As a spreadsheet numbers it, below the header row.
+		return fmt.Sprintf("%s: row %d", s.File, s.Entry+1)
+	}
+	return fmt.Sprintf("%s: entry %d", s.File, s.Entry)
+}
+
+// Problem is something wrong with one entry of a question file.
+type Problem struct {
+	Source
+	ID      int // 0 if the entry has none
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.ID == 0 {
+		return fmt.Sprintf("%s: %s", p.Source, p.Message)
+	}
+	return fmt.Sprintf("%s (id %d): %s", p.Source, p.ID, p.Message)
+}
+
+// ValidationError lists every problem found in a set of questions.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("%d problem(s) in the question bank:\n  %s", len(e.Problems), strings.Join(lines, "\n  "))
+}
+
+// NewQuestionBank validates and indexes questions. It rejects missing or
+// duplicate IDs, questions without text and answers that don't fit the
+// question's type, such as a choice answer that is not among the options,
+// and reports all of them at once.
+// sources says where each question came from, for the problem reports;
+// without it questions are numbered in order.
+func NewQuestionBank(questions []models.Question, sources []Source) (*QuestionBank, error) {
+	b := &QuestionBank{
+		questions:    slices.Clone(questions),
+		byID:         make(map[int]int, len(questions)),
+		byDifficulty: make(map[string][]int),
+		byCategory:   make(map[string][]int),
+	}
+	b.sources = make([]Source, len(questions))
+	for i := range b.sources {
+		b.sources[i] = Source{Entry: i + 1}
+		if i < len(sources) {
+			b.sources[i] = sources[i]
+		}
+	}
+	source := func(i int) Source { return b.sources[i] }
+
+	invalid := &ValidationError{}
+	for i, q := range b.questions {
+		problem := func(format string, args ...any) {
+			invalid.Problems = append(invalid.Problems, Problem{Source: source(i), ID: q.ID, Message: fmt.Sprintf(format, args...)})
+		}
+
+		if q.ID <= 0 {
+			problem("id must be a positive number")
+		} else if first, ok := b.byID[q.ID]; ok {
+			problem("duplicate id, already used by %s", source(first))
+		} else {
+			b.byID[q.ID] = i
+		}
+		if strings.TrimSpace(q.Text) == "" {
+			problem("text is empty")
+		}
+		for _, message := range checkAnswerFields(q) {
+			problem("%s", message)
+		}
+
+		b.byDifficulty[q.Difficulty] = append(b.byDifficulty[q.Difficulty], q.ID)
+		b.byCategory[q.Category] = append(b.byCategory[q.Category], q.ID)
+	}
+
+	if len(invalid.Problems) > 0 {
+		return nil, invalid
+	}
+	return b, nil
+}
+
+var questionTypes = []models.QuestionType{models.Choice, models.TrueFalse, models.FreeText, models.Numeric, models.MultiSelect}
+
+// checkAnswerFields checks the fields a question is answered with, which
+// depend on its type.
+func checkAnswerFields(q models.Question) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	kind := q.Kind()
+	if !slices.Contains(questionTypes, kind) {
+		problem("unknown type %q, use choice, true_false, text, numeric or multi_select", q.Type)
+		return problems
+	}
+
+	hasOptions := kind == models.Choice || kind == models.MultiSelect
+	if hasOptions {
+		if len(q.Options) == 0 {
+			problem("has no options")
+		}
+		for j, o := range q.Options {
+			if strings.TrimSpace(o) == "" {
+				problem("option %d is empty", j+1)
+			}
+		}
+	} else if len(q.Options) > 0 {
+		problem("options are not used by %s questions", kind)
+	}
+
+	switch kind {
+	case models.Choice:
+		if !slices.Contains(q.Options, q.CorrectAns) {
+			problem("correct_ans %q is not one of the options", q.CorrectAns)
+		}
+	case models.TrueFalse:
+		if _, err := strconv.ParseBool(strings.ToLower(strings.TrimSpace(q.CorrectAns))); err != nil {
+			problem("correct_ans %q is not true or false", q.CorrectAns)
+		}
+	case models.FreeText:
+		if strings.TrimSpace(q.CorrectAns) == "" {
+			problem("correct_ans is empty")
+		}
+		for j, alias := range q.Aliases {
+			if strings.TrimSpace(alias) == "" {
+				problem("alias %d is empty", j+1)
+			}
+		}
+	case models.Numeric:
+		if _, err := strconv.ParseFloat(strings.TrimSpace(q.CorrectAns), 64); err != nil {
+			problem("correct_ans %q is not a number", q.CorrectAns)
+		}
+		if q.Tolerance < 0 {
+			problem("tolerance must not be negative")
+		}
+	case models.MultiSelect:
+		if q.CorrectAns != "" {
+			problem("multi_select questions use correct_answers, not correct_ans")
+		}
+		if len(q.CorrectAnswers) == 0 {
+			problem("correct_answers is empty")
+		}
+		for _, a := range q.CorrectAnswers {
+			if !slices.Contains(q.Options, a) {
+				problem("correct_answers %q is not one of the options", a)
+			}
+		}
+	}
+
+	// Fields that only mean something for one type are likely a mistake
+	// elsewhere.
+	if len(q.Aliases) > 0 && kind != models.FreeText || q.Fuzzy && kind != models.FreeText {
+		problem("aliases and fuzzy are only used by text questions")
+	}
+	if q.Tolerance != 0 && kind != models.Numeric {
+		problem("tolerance is only used by numeric questions")
+	}
+	if len(q.CorrectAnswers) > 0 && kind != models.MultiSelect {
+		problem("correct_answers is only used by multi_select questions")
+	}
+	return problems
+}
+
+// Len returns the number of questions.
+func (b *QuestionBank) Len() int {
+	return len(b.questions)
+}
+
+// All returns every question in file order.
+func (b *QuestionBank) All() []models.Question {
+	return slices.Clone(b.questions)
+}
+
+// Get looks a question up by ID, including questions retired by a reload.
+func (b *QuestionBank) Get(id int) (models.Question, bool) {
+	i, ok := b.byID[id]
+	if !ok {
+		q, ok := b.retired[id]
+		return q, ok
+	}
+	return b.questions[i], true
+}
+
+// retire carries over every question of the previous bank that this one
+// dropped, so games in progress keep working. Call before the bank is
+// shared.
+func (b *QuestionBank) retire(previous *QuestionBank) {
+	b.retired = make(map[int]models.Question)
+	keep := func(q models.Question) {
+		if _, ok := b.byID[q.ID]; !ok {
+			b.retired[q.ID] = q
+		}
+	}
+	for _, q := range previous.retired {
+		keep(q)
+	}
+	for _, q := range previous.questions {
+		keep(q)
+	}
+}
+
+// ByDifficulty returns the questions of a difficulty, in file order.
+func (b *QuestionBank) ByDifficulty(difficulty string) []models.Question {
+	return b.lookup(b.byDifficulty[difficulty])
+}
+
+// ByCategory returns the questions of a category, in file order.
+func (b *QuestionBank) ByCategory(category string) []models.Question {
+	return b.lookup(b.byCategory[category])
+}
+
+// Categories returns the categories in use, sorted.
+func (b *QuestionBank) Categories() []string {
+	var out []string
+	for c := range b.byCategory {
+		if c != "" {
+			out = append(out, c)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func (b *QuestionBank) lookup(ids []int) []models.Question {
+	out := make([]models.Question, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, b.questions[b.byID[id]])
+	}
+	return out
+}
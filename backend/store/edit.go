@@ -0,0 +1,172 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"maze-game/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+)
+
+var (
+	// ErrQuestionNotFound is returned when editing a question that isn't
+	// in the bank.
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrQuestionExists is returned when creating a question with an ID
+	// that is already taken.
+	ErrQuestionExists = errors.New("question ID is already taken")
+)
+
+// Where LoadQuestions last loaded the bank from: a file or a directory.
+var loadedPath string
+
+// CreateQuestion adds a question to the bank and saves it. A question
+// without an ID gets the next free one. In a bank directory, new questions
+// go to questions.json.
+func CreateQuestion(q models.Question) (models.Question, error) {
+	err := editQuestions(func(b *QuestionBank, questions []models.Question, sources []Source) ([]models.Question, []Source, error) {
+		if q.ID == 0 {
+			q.ID = b.nextID()
+		} else if _, ok := b.Get(q.ID); ok {
+			// Retired IDs are taken too, games may still refer to them.
+			return nil, nil, fmt.Errorf("question %d: %w", q.ID, ErrQuestionExists)
+		}
+		file, err := newQuestionsFile()
+		if err != nil {
+			return nil, nil, err
+		}
+		return append(questions, q), append(sources, Source{File: file}), nil
+	})
+	return q, err
+}
+
+// UpdateQuestion replaces a question, keeping its ID and its place in its
+// file, and saves it.
+func UpdateQuestion(id int, q models.Question) (models.Question, error) {
+	q.ID = id
+	err := editQuestions(func(b *QuestionBank, questions []models.Question, sources []Source) ([]models.Question, []Source, error) {
+		i, ok := b.byID[id]
+		if !ok {
+			return nil, nil, ErrQuestionNotFound
+		}
+		questions[i] = q
+		return questions, sources, nil
+	})
+	return q, err
+}
+
+// DeleteQuestion removes a question from the bank and saves it. Games that
+// already have it can still answer it.
+func DeleteQuestion(id int) error {
+	return editQuestions(func(b *QuestionBank, questions []models.Question, sources []Source) ([]models.Question, []Source, error) {
+		i, ok := b.byID[id]
+		if !ok {
+			return nil, nil, ErrQuestionNotFound
+		}
+		return slices.Delete(questions, i, i+1), slices.Delete(sources, i, i+1), nil
+	})
+}
+
+// editQuestions applies a change to a copy of the bank, validates the
+// result, writes the files it touched and swaps it in. Nothing is written
+// if the change or the validation fails.
+func editQuestions(change func(b *QuestionBank, questions []models.Question, sources []Source) ([]models.Question, []Source, error)) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	current := Questions()
+	questions, sources, err := change(current, slices.Clone(current.questions), slices.Clone(current.sources))
+	if err != nil {
+		return err
+	}
+
+	// Number the entries as they will be written, so problems point at the
+	// right place.
+	perFile := make(map[string][]models.Question)
+	for i, s := range sources {
+		perFile[s.File] = append(perFile[s.File], questions[i])
+		sources[i].Entry = len(perFile[s.File])
+	}
+	updated, err := NewQuestionBank(questions, sources)
+	if err != nil {
+		return err
+	}
+
+	// Only rewrite the files that changed. A file that lost its last
+	// question is written empty rather than left stale.
+	before := make(map[string][]models.Question)
+	for i, s := range current.sources {
+		before[s.File] = append(before[s.File], current.questions[i])
+		if _, ok := perFile[s.File]; !ok {
+			perFile[s.File] = nil
+		}
+	}
+	for file, fileQuestions := range perFile {
+		if reflect.DeepEqual(before[file], fileQuestions) {
+			continue
+		}
+		if err := writeQuestions(file, fileQuestions); err != nil {
+			return err
+		}
+	}
+
+	updated.retire(current)
+	bank.Store(updated)
+	return nil
+}
+
+// nextID returns one more than the highest ID in use, retired or not.
+func (b *QuestionBank) nextID() int {
+	highest := 0
+	for _, q := range b.questions {
+		highest = max(highest, q.ID)
+	}
+	for id := range b.retired {
+		highest = max(highest, id)
+	}
+	return highest + 1
+}
+
+// newQuestionsFile is the file new questions are saved to.
+func newQuestionsFile() (string, error) {
+	if loadedPath == "" {
+		return "", errors.New("no question bank loaded")
+	}
+	info, err := os.Stat(loadedPath)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return filepath.Join(loadedPath, "questions.json"), nil
+	}
+	return loadedPath, nil
+}
+
+// writeQuestions saves questions to a file, in the file's format. It
+// writes a temporary file and renames it, so a reload never sees half a
+// file.
+func writeQuestions(file string, questions []models.Question) error {
+	data, err := encodeQuestions(file, questions)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".questions-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
@@ -0,0 +1,404 @@
+package store
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maze-game/models"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Question files are read and written in the format their extension
+// names. Anything that isn't CSV or YAML is JSON.
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatYAML = "yaml"
+)
+
+func fileFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return formatCSV
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatJSON
+}
+
+// decodeQuestions decodes a question file entry by entry, so one malformed
+// entry doesn't hide the problems in the others. Entries that can't be
+// decoded come back as problems, everything else as questions with where
+// they came from. The error is only set if the file can't be read as a
+// list of questions at all.
+func decodeQuestions(filePath string, data []byte) ([]models.Question, []Source, []Problem, error) {
+	switch fileFormat(filePath) {
+	case formatCSV:
+		return decodeCSV(filePath, data)
+	case formatYAML:
+		return decodeYAML(filePath, data)
+	}
+	return decodeJSON(filePath, data)
+}
+
+// encodeQuestions is the inverse of decodeQuestions.
+func encodeQuestions(filePath string, questions []models.Question) ([]byte, error) {
+	if questions == nil {
+		questions = []models.Question{}
+	}
+	switch fileFormat(filePath) {
+	case formatCSV:
+		return encodeCSV(questions)
+	case formatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(questions); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), encoder.Close()
+	}
+	data, err := json.MarshalIndent(questions, "", "  ")
+	return append(data, '\n'), err
+}
+
+// decodeJSON reads a JSON array of questions.
+func decodeJSON(filePath string, data []byte) ([]models.Question, []Source, []Problem, error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			line, col := position(data, syntax.Offset)
+			return nil, nil, nil, fmt.Errorf("%s:%d:%d: %v", filePath, line, col, err)
+		}
+		return nil, nil, nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+
+	var questions []models.Question
+	var sources []Source
+	var problems []Problem
+	for i, entry := range entries {
+		source := Source{File: filePath, Entry: i + 1}
+		var q models.Question
+		if err := json.Unmarshal(entry, &q); err != nil {
+			problems = append(problems, Problem{Source: source, Message: err.Error()})
+			continue
+		}
+		questions = append(questions, q)
+		sources = append(sources, source)
+	}
+	return questions, sources, problems, nil
+}
+
+// position turns a byte offset into a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	before := string(data[:min(int(offset), len(data))])
+	line := strings.Count(before, "\n") + 1
+	col := len(before) - strings.LastIndex(before, "\n")
+	return line, col
+}
+
+// decodeYAML reads a YAML list of questions, with the same fields as in
+// JSON.
+func decodeYAML(filePath string, data []byte) ([]models.Question, []Source, []Problem, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil, nil, nil // Empty file
+	}
+	list := root.Content[0]
+	if list.Kind != yaml.SequenceNode {
+		return nil, nil, nil, fmt.Errorf("%s:%d: expected a list of questions", filePath, list.Line)
+	}
+
+	var questions []models.Question
+	var sources []Source
+	var problems []Problem
+	for i, entry := range list.Content {
+		source := Source{File: filePath, Entry: i + 1}
+		var q models.Question
+		if err := entry.Decode(&q); err != nil {
+			message := err.Error()
+			var typeErr *yaml.TypeError
+			if errors.As(err, &typeErr) {
+				message = strings.Join(typeErr.Errors, "; ")
+			}
+			problems = append(problems, Problem{Source: source, Message: message})
+			continue
+		}
+		if q.ID <= 0 && hasKey(entry, "id") {
+			problems = append(problems, Problem{Source: source, Message: "id must be a positive number"})
+			continue
+		}
+		questions = append(questions, q)
+		sources = append(sources, source)
+	}
+	return questions, sources, problems, nil
+}
+
+// hasKey reports whether a YAML mapping has a key.
+func hasKey(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+// assignIDs gives questions from CSV and YAML files that have no id the
+// next free ones, in file order, and returns the files it numbered. IDs
+// retired from the bank in use are skipped, games may still refer to them.
+// A present but invalid id is already reported by the decoders, so an id
+// of 0 here means there was none.
+func assignIDs(questions []models.Question, sources []Source) map[string]bool {
+	next := Questions().nextID()
+	for _, q := range questions {
+		next = max(next, q.ID+1)
+	}
+	assigned := make(map[string]bool)
+	for i := range questions {
+		if questions[i].ID == 0 && fileFormat(sources[i].File) != formatJSON {
+			questions[i].ID = next
+			next++
+			assigned[sources[i].File] = true
+		}
+	}
+	return assigned
+}
+
+// A CSV file has a header row naming its columns, then one question per
+// row. Options, aliases and the answers to a multi_select question are
+// separated by csvListSeparator within their cell, or options can be
+// given one per column instead (option1, option2, ...).
+const csvListSeparator = "|"
+
+// CSV columns, by the names their header may use.
+const (
+	csvID         = "id"
+	csvType       = "type"
+	csvText       = "text"
+	csvOptions    = "options"
+	csvAnswer     = "answer"
+	csvDifficulty = "difficulty"
+	csvCategory   = "category"
+	csvAliases    = "aliases"
+	csvFuzzy      = "fuzzy"
+	csvTolerance  = "tolerance"
+)
+
+var csvColumnNames = map[string]string{
+	"id":              csvID,
+	"type":            csvType,
+	"text":            csvText,
+	"question":        csvText,
+	"options":         csvOptions,
+	"answer":          csvAnswer,
+	"correct_ans":     csvAnswer,
+	"correct_answer":  csvAnswer,
+	"correct_answers": csvAnswer,
+	"difficulty":      csvDifficulty,
+	"category":        csvCategory,
+	"aliases":         csvAliases,
+	"fuzzy":           csvFuzzy,
+	"tolerance":       csvTolerance,
+}
+
+// The columns encodeCSV writes.
+var csvColumns = []string{csvID, csvType, csvText, csvOptions, csvAnswer, csvDifficulty, csvCategory, csvAliases, csvFuzzy, csvTolerance}
+
+// csvColumn maps a header cell to a column, matching names loosely so
+// "Correct Answer" and "correct_answer" are the same.
+func csvColumn(header string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(header))
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+	if column, ok := csvColumnNames[name]; ok {
+		return column, true
+	}
+	if strings.HasPrefix(name, "option") {
+		return csvOptions, true
+	}
+	return "", false
+}
+
+// decodeCSV reads questions from a CSV file with a header row. Rows are
+// numbered like in a spreadsheet, so the first question is row 2.
+func decodeCSV(filePath string, data []byte) ([]models.Question, []Source, []Problem, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // Byte order mark from Excel
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // Checked per row below
+	reader.TrimLeadingSpace = true
+	// Spreadsheets set to a decimal comma export with semicolons.
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %v", filePath, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, nil, nil // Empty file
+	}
+
+	header := make([]string, len(rows[0]))
+	found := make(map[string]bool)
+	for i, cell := range rows[0] {
+		column, ok := csvColumn(cell)
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%s: unknown column %q, use %s", filePath, cell, strings.Join(csvColumns, ", "))
+		}
+		if found[column] && column != csvOptions {
+			return nil, nil, nil, fmt.Errorf("%s: column %q appears twice", filePath, column)
+		}
+		header[i] = column
+		found[column] = true
+	}
+	for _, required := range []string{csvText, csvAnswer} {
+		if !found[required] {
+			return nil, nil, nil, fmt.Errorf("%s: missing column %q", filePath, required)
+		}
+	}
+
+	var questions []models.Question
+	var sources []Source
+	var problems []Problem
+	for i, row := range rows[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue // Spreadsheets export empty rows as ",,,"
+		}
+		source := Source{File: filePath, Entry: i + 1}
+		q, rowProblems := csvQuestion(header, row)
+		for _, message := range rowProblems {
+			problems = append(problems, Problem{Source: source, ID: q.ID, Message: message})
+		}
+		if len(rowProblems) == 0 {
+			questions = append(questions, q)
+			sources = append(sources, source)
+		}
+	}
+	return questions, sources, problems, nil
+}
+
+// csvQuestion builds a question from one CSV row. Whether it makes sense
+// is left to NewQuestionBank; only cells that can't be read at all are
+// reported here.
+func csvQuestion(header, row []string) (models.Question, []string) {
+	var q models.Question
+	var problems []string
+	if len(row) != len(header) {
+		problems = append(problems, fmt.Sprintf("has %d columns, the header has %d", len(row), len(header)))
+	}
+
+	var answer string
+	for i, cell := range row[:min(len(row), len(header))] {
+		cell = strings.TrimSpace(cell)
+		switch header[i] {
+		case csvID:
+			if cell == "" {
+				break
+			}
+			id, err := strconv.Atoi(cell)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("id %q is not a number", cell))
+			} else if id <= 0 {
+				problems = append(problems, "id must be a positive number")
+			}
+			q.ID = id
+		case csvType:
+			q.Type = models.QuestionType(strings.ToLower(cell))
+		case csvText:
+			q.Text = cell
+		case csvOptions:
+			q.Options = append(q.Options, splitCSVList(cell)...)
+		case csvAnswer:
+			answer = cell
+		case csvDifficulty:
+			q.Difficulty = strings.ToLower(cell)
+		case csvCategory:
+			q.Category = cell
+		case csvAliases:
+			q.Aliases = splitCSVList(cell)
+		case csvFuzzy:
+			if cell == "" {
+				break
+			}
+			fuzzy, err := strconv.ParseBool(strings.ToLower(cell))
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("fuzzy %q is not true or false", cell))
+			}
+			q.Fuzzy = fuzzy
+		case csvTolerance:
+			if cell == "" {
+				break
+			}
+			tolerance, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("tolerance %q is not a number", cell))
+			}
+			q.Tolerance = tolerance
+		}
+	}
+
+	if q.Kind() == models.MultiSelect {
+		q.CorrectAnswers = splitCSVList(answer)
+	} else {
+		q.CorrectAns = answer
+	}
+	return q, problems
+}
+
+// splitCSVList splits a cell holding several values. An empty cell holds
+// none.
+func splitCSVList(cell string) []string {
+	if strings.TrimSpace(cell) == "" {
+		return nil
+	}
+	values := strings.Split(cell, csvListSeparator)
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// encodeCSV writes questions with every column, options in one cell.
+func encodeCSV(questions []models.Question) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(csvColumns)
+	for _, q := range questions {
+		answer := q.CorrectAns
+		if q.Kind() == models.MultiSelect {
+			answer = strings.Join(q.CorrectAnswers, csvListSeparator)
+		}
+		fuzzy, tolerance := "", ""
+		if q.Fuzzy {
+			fuzzy = "true"
+		}
+		if q.Tolerance != 0 {
+			tolerance = strconv.FormatFloat(q.Tolerance, 'g', -1, 64)
+		}
+		writer.Write([]string{
+			strconv.Itoa(q.ID),
+			string(q.Type),
+			q.Text,
+			strings.Join(q.Options, csvListSeparator),
+			answer,
+			q.Difficulty,
+			q.Category,
+			strings.Join(q.Aliases, csvListSeparator),
+			fuzzy,
+			tolerance,
+		})
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
@@ -1,31 +1,173 @@
 package store
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"maze-game/models"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Global variable to hold loaded questions.
-var QuestionBank []models.Question
+// The question bank in use. Swapped as a whole on reload, so readers
+// always see one complete, validated bank.
+var bank atomic.Pointer[QuestionBank]
+
+// Serializes loads, so two reloads can't race to retire questions.
+var loadMu sync.Mutex
+
+func init() {
+	bank.Store(&QuestionBank{})
+}
+
+// Questions returns the question bank in use.
+func Questions() *QuestionBank {
+	return bank.Load()
+}
+
+// LoadQuestions reads a question file, or every question file in a
+// directory, and makes it the question bank in use. Nothing changes if any
+// file has a problem. Questions the new bank drops stay available to
+// games that already use them.
+func LoadQuestions(path string) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
 
-// LoadQuestions reads the questions.json file and populates the QuestionBank.
-func LoadQuestions(filePath string) error {
 	// TODO: 1. Open the file.
-	file, err := os.ReadFile(filePath)
+	files, err := questionFiles(path)
 	if err != nil {
 		return err
 	}
 
 	// TODO: 2. Decode JSON.
-	err = json.Unmarshal(file, &QuestionBank)
-	if err != nil {
-		return err
+	var questions []models.Question
+	var sources []Source
+	invalid := &ValidationError{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		fileQuestions, fileSources, problems, err := decodeQuestions(file, data)
+		if err != nil {
+			return err
+		}
+		questions = append(questions, fileQuestions...)
+		sources = append(sources, fileSources...)
+		invalid.Problems = append(invalid.Problems, problems...)
 	}
 
 	// TODO: 3. Handle errors.
-	fmt.Println("Questions loaded successfully", QuestionBank)
+	assigned := assignIDs(questions, sources)
+	loaded, err := NewQuestionBank(questions, sources)
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		invalid.Problems = append(invalid.Problems, validation.Problems...)
+	} else if err != nil {
+		return err
+	}
+	if len(invalid.Problems) > 0 {
+		slices.SortStableFunc(invalid.Problems, func(a, b Problem) int {
+			if a.File != b.File {
+				return strings.Compare(a.File, b.File)
+			}
+			return a.Entry - b.Entry
+		})
+		return invalid
+	}
+
+	// Save the assigned ids, so they stay the same on the next reload.
+	for file := range assigned {
+		var fileQuestions []models.Question
+		for i, s := range sources {
+			if s.File == file {
+				fileQuestions = append(fileQuestions, questions[i])
+			}
+		}
+		if err := writeQuestions(file, fileQuestions); err != nil {
+			log.Println("Question bank: ids not saved:", err)
+		}
+	}
 
+	loaded.retire(Questions())
+	bank.Store(loaded)
+	loadedPath = path
+	fmt.Println("Questions loaded successfully:", loaded.Len(), "from", len(files), "file(s) in", path)
 	return nil
 }
+
+// WatchQuestions polls the question file or directory and reloads the bank
+// whenever a file changes, appears or goes away. A reload that fails is
+// logged and the current bank stays in use.
+func WatchQuestions(path string, interval time.Duration) {
+	last, _ := fingerprint(path)
+	go func() {
+		for range time.Tick(interval) {
+			current, err := fingerprint(path)
+			if err != nil {
+				log.Println("Question bank:", err)
+				continue
+			}
+			if current == last {
+				continue
+			}
+			last = current
+			if err := LoadQuestions(path); err != nil {
+				log.Println("Question bank not reloaded:", err)
+			}
+		}
+	}()
+}
+
+// File extensions of question files in a bank directory.
+var questionExtensions = []string{".json", ".csv", ".yaml", ".yml"}
+
+// questionFiles lists the question files at path: the file itself, or the
+// bank files in a directory in name order.
+func questionFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && slices.Contains(questionExtensions, strings.ToLower(filepath.Ext(e.Name()))) {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s: no question files", path)
+	}
+	return files, nil
+}
+
+// fingerprint sums up the names, sizes and modification times of the
+// question files, to notice when any of them changes.
+func fingerprint(path string) (string, error) {
+	files, err := questionFiles(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s|%d|%d\n", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}